@@ -3,10 +3,22 @@ package collection
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"sort"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/remychantenay/fuego/collection/internal"
+	"github.com/remychantenay/fuego/geo"
+	"github.com/remychantenay/fuego/internal/errs"
+	"github.com/remychantenay/fuego/internal/ratelimit"
+	"github.com/remychantenay/fuego/internal/retry"
 	"google.golang.org/api/iterator"
+	"google.golang.org/genproto/googleapis/type/latlng"
 )
 
 // Collection provides the necessary to interact with a Firestore collection.
@@ -20,6 +32,9 @@ type Collection interface {
 	// RetrieveWith retrieve documents from a collection using the provided Query.
 	RetrieveWith(ctx context.Context, sample interface{}, query firestore.Query) ([]interface{}, error)
 
+	// WithinRadius retrieves the documents which location (a geo.Point field) is within a radius of a center, nearest first.
+	WithinRadius(ctx context.Context, sample interface{}, field string, center *latlng.LatLng, radius float64) ([]interface{}, error)
+
 	// SetForAll sets a field with a given value for ALL documents in the collection.
 	//
 	// Note: uses Batched writes
@@ -41,33 +56,46 @@ type FirestoreCollection struct {
 	firestore.Query
 
 	fsClient *firestore.Client
+
+	opts *options
 }
 
 // New creates and returns a new FirestoreCollection.
-func New(fs *firestore.Client, path string) *FirestoreCollection {
+func New(fs *firestore.Client, path string, opts ...Option) *FirestoreCollection {
 	r := fs.Collection(path)
 	return &FirestoreCollection{
 		Ref:      r,
 		Query:    r.Query,
 		fsClient: fs,
+		opts:     newOptions(opts...),
 	}
 }
 
 // Retrieve retrieve all the documents from a collection.
 //  values, err := fuego.Collection("users").Retrieve(ctx, &User{})
 func (c *FirestoreCollection) Retrieve(ctx context.Context, sample interface{}) ([]interface{}, error) {
+	c.opts.logger().Operation(ctx, "Collection.Retrieve", c.Ref.Path)
+
 	result := make([]interface{}, 0)
 	it := c.Documents(ctx)
 	for {
+		if err := ratelimit.Wait(ctx, c.opts.rateLimiter(), 1); err != nil {
+			return nil, errs.Wrap("Collection.Retrieve", c.Ref.Path, "", err)
+		}
+
 		doc, err := it.Next()
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
+			err = errs.Wrap("Collection.Retrieve", c.Ref.Path, "", err)
+			c.opts.logger().Failure(ctx, "Collection.Retrieve", c.Ref.Path, err)
 			return nil, err
 		}
 
 		if err := doc.DataTo(sample); err != nil {
+			err = errs.New("Collection.Retrieve", doc.Ref.Path, "", errs.TypeMismatch, err)
+			c.opts.logger().Failure(ctx, "Collection.Retrieve", doc.Ref.Path, err)
 			return nil, err
 		}
 
@@ -83,13 +111,111 @@ func (c *FirestoreCollection) RetrieveWith(ctx context.Context, sample interface
 	return c.Retrieve(ctx, sample)
 }
 
+// WithinRadius retrieves the documents which location (a geo.Point field) is within radius meters of center, nearest first.
+//
+// One query is run per range of geohashes covering the circle (up to 9), the embedded Query being used as a base
+// (e.g. to add equality filters). The documents are then filtered by their precise distance.
+// Unlike Retrieve, each document is decoded into a new value of the type of sample.
+// An InvalidArgument error wrapping geo.ErrInvalidLocation or geo.ErrInvalidRadius is returned
+// if the center or the radius is invalid.
+//  center := &latlng.LatLng{Latitude: 48.8566, Longitude: 2.3522}
+//  values, err := fuego.Collection("shops").WithinRadius(ctx, &Shop{}, "Location", center, 1000)
+func (c *FirestoreCollection) WithinRadius(ctx context.Context, sample interface{}, field string, center *latlng.LatLng, radius float64) ([]interface{}, error) {
+	const op = "Collection.WithinRadius"
+	c.opts.logger().Operation(ctx, op, c.Ref.Path, slog.String("field", field), slog.Float64("radius", radius))
+
+	fail := func(path string, err error) error {
+		err = errs.Wrap(op, path, field, err)
+		c.opts.logger().Failure(ctx, op, path, err)
+		return err
+	}
+	invalid := func(err error) error {
+		return fail(c.Ref.Path, errs.New(op, c.Ref.Path, field, errs.InvalidArgument, err))
+	}
+
+	if err := geo.Validate(center); err != nil {
+		return nil, invalid(err)
+	}
+	if math.IsNaN(radius) || radius <= 0 {
+		return nil, invalid(fmt.Errorf("%w: %g", geo.ErrInvalidRadius, radius))
+	}
+	if t := reflect.TypeOf(sample); t == nil || t.Kind() != reflect.Pointer {
+		return nil, invalid(errors.New("sample is not a pointer"))
+	}
+
+	type match struct {
+		value    interface{}
+		distance float64
+	}
+
+	var matches []match
+	seen := make(map[string]bool)
+	for _, r := range geo.QueryRanges(center, radius) {
+		it := c.OrderBy(field+"."+geo.GeohashField, firestore.Asc).StartAt(r.Start).EndAt(r.End).Documents(ctx)
+		for {
+			if err := ratelimit.Wait(ctx, c.opts.rateLimiter(), 1); err != nil {
+				it.Stop()
+				return nil, fail(c.Ref.Path, err)
+			}
+
+			doc, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				it.Stop()
+				return nil, fail(c.Ref.Path, err)
+			}
+
+			if seen[doc.Ref.Path] {
+				continue
+			}
+			seen[doc.Ref.Path] = true
+
+			// Documents without a geopoint can't be within the radius
+			v, err := doc.DataAt(field + "." + geo.GeoPointField)
+			point, ok := v.(*latlng.LatLng)
+			if err != nil || !ok {
+				continue
+			}
+
+			distance := geo.Distance(center, point)
+			if distance > radius {
+				continue
+			}
+
+			value := reflect.New(reflect.TypeOf(sample).Elem()).Interface()
+			if err := doc.DataTo(value); err != nil {
+				it.Stop()
+				return nil, fail(doc.Ref.Path, errs.New(op, doc.Ref.Path, field, errs.TypeMismatch, err))
+			}
+			matches = append(matches, match{value: value, distance: distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+
+	result := make([]interface{}, len(matches))
+	for i, m := range matches {
+		result[i] = m.value
+	}
+	return result, nil
+}
+
 // SetForAll will set a field with a given value for ALL documents in the collection.
 //  err := fuego.Collection("users").SetForAll(ctx, "NewField", "NewValue")
 func (c *FirestoreCollection) SetForAll(ctx context.Context, fieldName string, fieldValue interface{}) error {
+	c.opts.logger().Operation(ctx, "Collection.SetForAll", c.Ref.Path,
+		slog.String("field", fieldName), c.opts.logger().Value(fieldName, fieldValue))
+
 	it := c.Ref.DocumentRefs(ctx)
 	documentRefs, err := it.GetAll()
 	if err != nil {
-		return nil
+		err = errs.Wrap("Collection.SetForAll", c.Ref.Path, "", err)
+		c.opts.logger().Failure(ctx, "Collection.SetForAll", c.Ref.Path, err)
+		return err
 	}
 
 	writeMap := map[string]interface{}{
@@ -120,23 +246,20 @@ func (c *FirestoreCollection) SetForAll(ctx context.Context, fieldName string, f
 	}
 
 	// 3. Committing all batches
-	for i := 0; i < len(batches); i++ {
-		_, err := batches[i].Commit(ctx)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return c.commitBatches(ctx, "Collection.SetForAll", batches, len(documentRefs))
 }
 
 // DeleteAll removes all items from the collection.
 //  err := fuego.Collection("users").DeleteAll(ctx)
 func (c *FirestoreCollection) DeleteAll(ctx context.Context) error {
+	c.opts.logger().Operation(ctx, "Collection.DeleteAll", c.Ref.Path)
+
 	it := c.Ref.DocumentRefs(ctx)
 	documentRefs, err := it.GetAll()
 	if err != nil {
-		return nil
+		err = errs.Wrap("Collection.DeleteAll", c.Ref.Path, "", err)
+		c.opts.logger().Failure(ctx, "Collection.DeleteAll", c.Ref.Path, err)
+		return err
 	}
 
 	// 1. Preparing the batches
@@ -163,11 +286,43 @@ func (c *FirestoreCollection) DeleteAll(ctx context.Context) error {
 	}
 
 	// 3. Committing all batches
+	return c.commitBatches(ctx, "Collection.DeleteAll", batches, len(documentRefs))
+}
+
+// commitBatches commits the given batches one after the other, stopping at the first failure.
+//
+// Each commit is throttled by the bulk limiter and retried according to the retry policy of the operation.
+// The batches committed before a failure are NOT rolled back.
+func (c *FirestoreCollection) commitBatches(ctx context.Context, op string, batches []*firestore.WriteBatch, operationCount int) error {
+	policy := c.opts.retryPolicy(op)
 	for i := 0; i < len(batches); i++ {
-		_, err := batches[i].Commit(ctx)
+		batch := batches[i]
+		batchOpCount := min(internal.MaxOperationsPerBatchedWrite, operationCount-i*internal.MaxOperationsPerBatchedWrite)
+		err := policy.Do(ctx, retry.Idempotent, func(ctx context.Context) error {
+			if err := ratelimit.Wait(ctx, c.opts.bulkRateLimiter(), batchOpCount); err != nil {
+				return err
+			}
+
+			_, err := batch.Commit(ctx)
+			return err
+		}, func(attempt int, wait time.Duration, err error) {
+			c.opts.logger().Retry(ctx, op, c.Ref.Path, attempt, wait, err)
+		})
 		if err != nil {
+			err = errs.Wrap(op, c.Ref.Path, "", err)
+			c.opts.logger().Failure(ctx, op, c.Ref.Path, err,
+				slog.Int("batch", i+1),
+				slog.Int("batches", len(batches)),
+				slog.Int("committed_batches", i),
+				slog.Int("operations", operationCount),
+			)
 			return err
 		}
+
+		c.opts.logger().BatchCommit(ctx, op, c.Ref.Path,
+			slog.Int("batch", i+1),
+			slog.Int("batches", len(batches)),
+		)
 	}
 
 	return nil
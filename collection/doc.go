@@ -23,6 +23,18 @@ This allow to directly use its methods:
 	query := collection.Where("FirstName", "==", "John").Limit(50)
 	users, err := collection.RetrieveWith(ctx, &User{}, query)
 
+Documents which timestamp field falls within a day, a week or a month of a given location can be queried as follow:
+
+	loc, err := fuego.Location("America/Los_Angeles")
+	users := fuego.Collection("users")
+	query := collection.DayRange("LastSeenAt", time.Now(), loc).Query(users.Query)
+	values, err := users.RetrieveWith(ctx, &User{}, query)
+
+Documents which location (a geo.Point field) is within a radius (in meters) of a center can be retrieved, nearest first:
+
+	center := &latlng.LatLng{Latitude: 48.8566, Longitude: 2.3522}
+	shops, err := fuego.Collection("shops").WithinRadius(ctx, &Shop{}, "Location", center, 1000)
+
 Fuego also provide with the ability to set a value for a field for all documents within a given collection:
 
 	err := fuego.Collection("users").SetForAll(ctx, "Premium", true) // Yay!
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"time"
+)
+
+// Day returns the boundaries of the day of t in loc: its start (inclusive) and the start of the next day (exclusive).
+func Day(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	loc = orUTC(loc)
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// Week returns the boundaries of the week (starting on Monday) of t in loc: its start (inclusive)
+// and the start of the next week (exclusive).
+func Week(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	loc = orUTC(loc)
+	t = t.In(loc)
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	start := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 7)
+}
+
+// Month returns the boundaries of the month of t in loc: its start (inclusive) and the start of the next month (exclusive).
+func Month(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	loc = orUTC(loc)
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// orUTC returns loc, or UTC if loc is nil.
+func orUTC(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
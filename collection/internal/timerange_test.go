@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeRanges(t *testing.T) {
+	dublin, err := time.LoadLocation("Europe/Dublin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sunday 2024-03-31 is a DST transition day in Dublin (23 hours long)
+	at := time.Date(2024, time.March, 31, 15, 30, 0, 0, dublin)
+
+	tests := []struct {
+		description string
+		fn          func(time.Time, *time.Location) (time.Time, time.Time)
+		with        time.Time
+		wantStart   time.Time
+		wantEnd     time.Time
+	}{
+		{
+			description: "Day",
+			fn:          Day,
+			with:        at,
+			wantStart:   time.Date(2024, time.March, 31, 0, 0, 0, 0, dublin),
+			wantEnd:     time.Date(2024, time.April, 1, 0, 0, 0, 0, dublin),
+		},
+		{
+			description: "Day of a time in another location",
+			fn:          Day,
+			with:        time.Date(2024, time.March, 31, 23, 30, 0, 0, time.UTC), // 00:30 in Dublin
+			wantStart:   time.Date(2024, time.April, 1, 0, 0, 0, 0, dublin),
+			wantEnd:     time.Date(2024, time.April, 2, 0, 0, 0, 0, dublin),
+		},
+		{
+			description: "Week ending on a Sunday",
+			fn:          Week,
+			with:        at,
+			wantStart:   time.Date(2024, time.March, 25, 0, 0, 0, 0, dublin),
+			wantEnd:     time.Date(2024, time.April, 1, 0, 0, 0, 0, dublin),
+		},
+		{
+			description: "Week starting on a Monday",
+			fn:          Week,
+			with:        time.Date(2024, time.April, 1, 0, 0, 0, 0, dublin),
+			wantStart:   time.Date(2024, time.April, 1, 0, 0, 0, 0, dublin),
+			wantEnd:     time.Date(2024, time.April, 8, 0, 0, 0, 0, dublin),
+		},
+		{
+			description: "Month",
+			fn:          Month,
+			with:        at,
+			wantStart:   time.Date(2024, time.March, 1, 0, 0, 0, 0, dublin),
+			wantEnd:     time.Date(2024, time.April, 1, 0, 0, 0, 0, dublin),
+		},
+		{
+			description: "Month in December",
+			fn:          Month,
+			with:        time.Date(2024, time.December, 31, 23, 59, 0, 0, dublin),
+			wantStart:   time.Date(2024, time.December, 1, 0, 0, 0, 0, dublin),
+			wantEnd:     time.Date(2025, time.January, 1, 0, 0, 0, 0, dublin),
+		},
+	}
+
+	for _, test := range tests {
+		start, end := test.fn(test.with, dublin)
+
+		if !start.Equal(test.wantStart) || !end.Equal(test.wantEnd) {
+			t.Fatalf("%s -> Got [%v, %v) but expected [%v, %v)", test.description, start, end, test.wantStart, test.wantEnd)
+		}
+	}
+}
+
+func TestTimeRanges_NilLocation(t *testing.T) {
+	at := time.Date(2024, time.March, 31, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60)) // 01:30 on April 1st in UTC
+
+	for description, fn := range map[string]func(time.Time, *time.Location) (time.Time, time.Time){"Day": Day, "Week": Week, "Month": Month} {
+		start, end := fn(at, nil)
+		wantStart, wantEnd := fn(at, time.UTC)
+
+		if !start.Equal(wantStart) || !end.Equal(wantEnd) || start.Location() != time.UTC {
+			t.Fatalf("%s -> Got [%v, %v) but expected [%v, %v)", description, start, end, wantStart, wantEnd)
+		}
+	}
+
+	start, _ := Day(at, nil)
+	if want := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Fatalf("Got %v but expected %v", start, want)
+	}
+}
@@ -0,0 +1,101 @@
+package collection
+
+import (
+	"log/slog"
+
+	"github.com/remychantenay/fuego/internal/logging"
+	"github.com/remychantenay/fuego/internal/ratelimit"
+	"github.com/remychantenay/fuego/internal/retry"
+)
+
+// Option configures a FirestoreCollection.
+type Option func(*options)
+
+// WithLogger sets the logger used by the collection (nothing is logged by default).
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.slog = l
+	}
+}
+
+// WithLogRedaction sets how field values appear in the logs (all of them are redacted by default).
+func WithLogRedaction(r func(field string, value interface{}) interface{}) Option {
+	return func(o *options) {
+		o.redact = r
+	}
+}
+
+// WithRetryPolicies sets the retry policies applied to the batch commits.
+func WithRetryPolicies(p *retry.Policies) Option {
+	return func(o *options) {
+		o.retry = p
+	}
+}
+
+// WithLimiter sets the limiter throttling the reads.
+func WithLimiter(l ratelimit.Limiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// WithBulkLimiter sets the limiter throttling the bulk writes (e.g. SetForAll, DeleteAll).
+func WithBulkLimiter(l ratelimit.Limiter) Option {
+	return func(o *options) {
+		o.bulkLimiter = l
+	}
+}
+
+// options holds the client-wide settings used by a collection.
+//
+// A nil *options is valid and falls back to the defaults.
+type options struct {
+	slog        *slog.Logger
+	redact      logging.Redactor
+	log         *logging.Logger
+	retry       *retry.Policies
+	limiter     ratelimit.Limiter
+	bulkLimiter ratelimit.Limiter
+}
+
+// newOptions creates and returns options with the provided Option(s) applied.
+func newOptions(opts ...Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.log = logging.New(o.slog, o.redact)
+	return o
+}
+
+// logger returns the logger (if any), nil otherwise.
+func (o *options) logger() *logging.Logger {
+	if o == nil {
+		return nil
+	}
+	return o.log
+}
+
+// retryPolicy returns the retry policy applying to a given operation.
+func (o *options) retryPolicy(op string) retry.Policy {
+	if o == nil {
+		return retry.Policy{}
+	}
+	return o.retry.For(op)
+}
+
+// rateLimiter returns the limiter throttling the reads (if any), nil otherwise.
+func (o *options) rateLimiter() ratelimit.Limiter {
+	if o == nil {
+		return nil
+	}
+	return o.limiter
+}
+
+// bulkRateLimiter returns the limiter throttling the bulk writes (if any), nil otherwise.
+func (o *options) bulkRateLimiter() ratelimit.Limiter {
+	if o == nil {
+		return nil
+	}
+	return o.bulkLimiter
+}
@@ -0,0 +1,55 @@
+package collection
+
+import (
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/remychantenay/fuego/collection/internal"
+)
+
+// TimestampRange is a time window, used to query the documents which timestamp field falls within it.
+type TimestampRange struct {
+
+	// Field is the name of the timestamp field.
+	Field string
+
+	// Start is the start of the window (inclusive).
+	Start time.Time
+
+	// End is the end of the window (exclusive).
+	End time.Time
+}
+
+// DayRange returns the range covering the day of t in the given location (e.g. from midnight to midnight in Los Angeles),
+// UTC if the location is nil.
+//  r := collection.DayRange("LastSeenAt", time.Now(), loc)
+func DayRange(field string, t time.Time, loc *time.Location) TimestampRange {
+	start, end := internal.Day(t, loc)
+	return TimestampRange{Field: field, Start: start, End: end}
+}
+
+// WeekRange returns the range covering the week (starting on Monday) of t in the given location (UTC if nil).
+//  r := collection.WeekRange("LastSeenAt", time.Now(), loc)
+func WeekRange(field string, t time.Time, loc *time.Location) TimestampRange {
+	start, end := internal.Week(t, loc)
+	return TimestampRange{Field: field, Start: start, End: end}
+}
+
+// MonthRange returns the range covering the month of t in the given location (UTC if nil).
+//  r := collection.MonthRange("LastSeenAt", time.Now(), loc)
+func MonthRange(field string, t time.Time, loc *time.Location) TimestampRange {
+	start, end := internal.Month(t, loc)
+	return TimestampRange{Field: field, Start: start, End: end}
+}
+
+// Query returns q restricted to the documents which timestamp field falls within the range.
+//  users := fuego.Collection("users")
+//  values, err := users.RetrieveWith(ctx, &User{}, collection.DayRange("LastSeenAt", time.Now(), loc).Query(users.Query))
+func (r TimestampRange) Query(q firestore.Query) firestore.Query {
+	return q.Where(r.Field, ">=", r.Start).Where(r.Field, "<", r.End)
+}
+
+// Contains returns true if t falls within the range.
+func (r TimestampRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
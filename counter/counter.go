@@ -0,0 +1,239 @@
+// Package counter provides distributed counters, spread over several documents (shards) to sustain high write rates.
+package counter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/remychantenay/fuego/counter/internal"
+	"github.com/remychantenay/fuego/internal/errs"
+	"github.com/remychantenay/fuego/internal/ratelimit"
+	"github.com/remychantenay/fuego/internal/retry"
+)
+
+// MaxShards is the max. number of shards of a counter.
+const MaxShards = internal.MaxShards
+
+// Counter provides the necessary to interact with a distributed counter.
+type Counter interface {
+
+	// Increment adds delta (possibly negative) to the counter.
+	Increment(ctx context.Context, delta int64) error
+
+	// Value returns the value of the counter.
+	Value(ctx context.Context) (int64, error)
+
+	// Reshard changes the number of shards of the counter, its value being preserved.
+	Reshard(ctx context.Context, shards int) error
+}
+
+// FirestoreCounter is a counter which value is spread over shards, stored as documents
+// in the "shards" subcollection of the counter document.
+//
+// Each shard document sustains about one write per second, the counter sustains about as many writes per second
+// as it has shards. Reading the value costs one aggregation query, whatever the number of shards.
+type FirestoreCounter struct {
+
+	// Ref is the reference of the counter document (which doesn't need to exist).
+	Ref *firestore.DocumentRef
+
+	fsClient *firestore.Client
+
+	opts *options
+
+	mu sync.Mutex
+
+	shards int
+}
+
+// New creates and returns a new FirestoreCounter with the given number of shards (1 to MaxShards).
+//
+// The number of shards is validated by the operations using it: with a number of shards out of range,
+// Increment returns an InvalidArgument error wrapping ErrInvalidShardCount (see Reshard).
+//
+// The operations of a counter are applied directly, they can't be part of a transaction or a write batch.
+func New(fs *firestore.Client, path, documentID string, shards int, opts ...Option) *FirestoreCounter {
+	return &FirestoreCounter{
+		Ref:      fs.Collection(path).Doc(documentID),
+		fsClient: fs,
+		opts:     newOptions(opts...),
+		shards:   shards,
+	}
+}
+
+// Shards returns the number of shards of the counter.
+func (c *FirestoreCounter) Shards() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.shards
+}
+
+// Increment adds delta (possibly negative) to one of the shards of the counter, picked at random.
+//
+// The shard is created if needed. Increments are not retried on failure, as they may have been applied.
+//  err := fuego.Counter("posts", "123", 10).Increment(ctx, 1)
+func (c *FirestoreCounter) Increment(ctx context.Context, delta int64) error {
+	const op = "Counter.Increment"
+
+	shards := c.Shards()
+	if err := validate(shards); err != nil {
+		return c.fail(ctx, op, err)
+	}
+
+	shard := rand.Intn(shards)
+	return c.run(ctx, op, retry.NonIdempotent, func(ctx context.Context) error {
+		_, err := c.shard(shard).Set(ctx, map[string]interface{}{
+			internal.Field: firestore.Increment(delta),
+		}, firestore.MergeAll)
+		return err
+	}, slog.Int("shard", shard), c.opts.logger().Value(internal.Field, delta))
+}
+
+// Value returns the value of the counter, i.e. the sum of its shards.
+//
+// All the shards are summed, including the ones written by clients using a different number of shards (see Reshard).
+//  value, err := fuego.Counter("posts", "123", 10).Value(ctx)
+func (c *FirestoreCounter) Value(ctx context.Context) (int64, error) {
+	const op = "Counter.Value"
+
+	var value int64
+	err := c.run(ctx, op, retry.Idempotent, func(ctx context.Context) error {
+		result, err := c.Ref.Collection(internal.Collection).NewAggregationQuery().
+			WithSum(internal.Field, "sum").
+			Get(ctx)
+		if err != nil {
+			return err
+		}
+
+		value, err = internal.ToInt64(result.Data()["sum"])
+		if err != nil {
+			return errs.New(op, c.Ref.Path, "", errs.TypeMismatch, err)
+		}
+		return nil
+	})
+
+	return value, err
+}
+
+// Reshard changes the number of shards (1 to MaxShards) of the counter while it is in use, its value being preserved.
+//
+// The counts of the shards beyond the new number of shards are moved to the first shard, inside a transaction.
+// Clients still using the previous number of shards keep on working: the shards they recreate are summed as well,
+// until they are moved by another Reshard.
+//  err := fuego.Counter("posts", "123", 10).Reshard(ctx, 50)
+func (c *FirestoreCounter) Reshard(ctx context.Context, shards int) error {
+	const op = "Counter.Reshard"
+
+	if err := validate(shards); err != nil {
+		return c.fail(ctx, op, err, slog.Int("shards", shards))
+	}
+
+	err := c.run(ctx, op, retry.NonIdempotent, func(ctx context.Context) error {
+		attempt := 0
+		return c.fsClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			attempt++
+			if attempt > 1 {
+				c.opts.logger().TransactionRetry(ctx, op, c.Ref.Path, attempt)
+			}
+
+			snapshots, err := tx.Documents(c.Ref.Collection(internal.Collection)).GetAll()
+			if err != nil {
+				return err
+			}
+
+			var moved int64
+			var excess []*firestore.DocumentRef
+			for _, s := range snapshots {
+				if i, ok := internal.ShardIndex(s.Ref.ID); ok && i < shards {
+					continue
+				}
+
+				count, err := internal.ToInt64(s.Data()[internal.Field])
+				if err != nil {
+					return errs.New(op, s.Ref.Path, internal.Field, errs.TypeMismatch, err)
+				}
+				moved += count
+				excess = append(excess, s.Ref)
+			}
+
+			if len(excess) == 0 {
+				return nil
+			}
+
+			err = tx.Set(c.shard(0), map[string]interface{}{
+				internal.Field: firestore.Increment(moved),
+			}, firestore.MergeAll)
+			if err != nil {
+				return err
+			}
+
+			for _, ref := range excess {
+				if err := tx.Delete(ref); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}, slog.Int("shards", shards))
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.shards = shards
+	c.mu.Unlock()
+	return nil
+}
+
+// shard returns the reference of the i-th shard.
+func (c *FirestoreCounter) shard(i int) *firestore.DocumentRef {
+	return c.Ref.Collection(internal.Collection).Doc(internal.ShardID(i))
+}
+
+// run executes an operation, retries it according to its policy and logs its outcome.
+//
+// Each attempt is subject to rate limiting. The returned error (if any) is an *errs.Error.
+func (c *FirestoreCounter) run(ctx context.Context, op string, idem retry.Idempotency, fn func(ctx context.Context) error, attrs ...slog.Attr) error {
+	c.opts.logger().Operation(ctx, op, c.Ref.Path, attrs...)
+
+	attempt := func(ctx context.Context) error {
+		if err := ratelimit.Wait(ctx, c.opts.rateLimiter(), 1); err != nil {
+			return err
+		}
+		return fn(ctx)
+	}
+
+	err := c.opts.retryPolicy(op).Do(ctx, idem, attempt, func(attempt int, wait time.Duration, err error) {
+		c.opts.logger().Retry(ctx, op, c.Ref.Path, attempt, wait, err)
+	})
+	if err != nil {
+		return c.fail(ctx, op, err, attrs...)
+	}
+
+	return nil
+}
+
+// fail classifies the error of an operation, logs it and returns it.
+func (c *FirestoreCounter) fail(ctx context.Context, op string, err error, attrs ...slog.Attr) error {
+	if errors.Is(err, ErrInvalidShardCount) {
+		err = errs.New(op, c.Ref.Path, "", errs.InvalidArgument, err)
+	}
+
+	err = errs.Wrap(op, c.Ref.Path, "", err)
+	c.opts.logger().Failure(ctx, op, c.Ref.Path, err, attrs...)
+	return err
+}
+
+// validate returns ErrInvalidShardCount if the number of shards is out of range.
+func validate(shards int) error {
+	if shards < 1 || shards > MaxShards {
+		return fmt.Errorf("%w: %d", ErrInvalidShardCount, shards)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+/*
+Package counter provides distributed counters, spread over several documents (shards) to sustain high write rates.
+
+A single document sustains about one write per second. A counter spreads its value over N shard documents
+(stored in the "shards" subcollection of the counter document) and sustains about N writes per second.
+
+Usage
+
+Incrementing a counter adds the given delta to one of its shards, picked at random:
+
+	counter := fuego.Counter("posts", "123", 10) // 10 shards
+	err := counter.Increment(ctx, 1)
+
+Reading the value of a counter sums its shards, with a single aggregation query:
+
+	value, err := counter.Value(ctx)
+
+The number of shards can be changed while the counter is in use, its value being preserved:
+
+	err := counter.Reshard(ctx, 50)
+
+The operations of a counter are always applied directly: a counter ignores the transaction
+or write batch started on the client (if any).
+*/
+package counter
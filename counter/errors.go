@@ -0,0 +1,10 @@
+package counter
+
+import "errors"
+
+// The errors returned by the counter operations are *fuego.Error values wrapping the errors below (when relevant),
+// they can be matched with errors.Is.
+var (
+	// ErrInvalidShardCount indicates that the number of shards of a counter is out of range (1 to MaxShards).
+	ErrInvalidShardCount = errors.New("counter: invalid shard count")
+)
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	// Collection is the name of the subcollection holding the shards of a counter.
+	Collection = "shards"
+
+	// Field is the name of the field holding the count of a shard.
+	Field = "Count"
+
+	// MaxShards is the max. number of shards of a counter, so that resharding fits in a single transaction
+	// (i.e. 500 writes).
+	MaxShards = 499
+)
+
+// ShardID returns the ID of the document of the i-th shard.
+func ShardID(i int) string {
+	return strconv.Itoa(i)
+}
+
+// ShardIndex returns the index of a shard given the ID of its document, ok is false if the ID isn't a shard ID.
+func ShardIndex(id string) (i int, ok bool) {
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 || ShardID(i) != id {
+		return 0, false
+	}
+	return i, true
+}
+
+// ToInt64 converts a count (e.g. a sum computed by Firestore) to an integer.
+//
+// Firestore returns a double when a sum overflows, which results in an error.
+func ToInt64(v interface{}) (int64, error) {
+	switch x := v.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return x, nil
+	case float64:
+		if x == math.Trunc(x) && x >= math.MinInt64 && x < math.MaxInt64 {
+			return int64(x), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid count %v (%T)", v, v)
+}
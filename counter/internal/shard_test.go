@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestShardIndex(t *testing.T) {
+
+	tests := []struct {
+		with   string
+		want   int
+		wantOk bool
+	}{
+		{with: "0", want: 0, wantOk: true},
+		{with: "42", want: 42, wantOk: true},
+		{with: "042"},
+		{with: "-1"},
+		{with: "+1"},
+		{with: "shard"},
+		{with: ""},
+	}
+
+	for _, test := range tests {
+		result, ok := ShardIndex(test.with)
+
+		if ok != test.wantOk || result != test.want {
+			t.Fatalf("%q -> Got %d (%t) but expected %d (%t)", test.with, result, ok, test.want, test.wantOk)
+		}
+	}
+}
+
+func TestShardIndex_RoundTrip(t *testing.T) {
+	for i := 0; i < MaxShards; i++ {
+		if result, ok := ShardIndex(ShardID(i)); !ok || result != i {
+			t.Fatalf("Got %d (%t) but expected %d", result, ok, i)
+		}
+	}
+}
+
+func TestToInt64(t *testing.T) {
+
+	tests := []struct {
+		description string
+		with        interface{}
+		want        int64
+		wantErr     bool
+	}{
+		{
+			description: "Integer",
+			with:        int64(42),
+			want:        42,
+		},
+		{
+			description: "Integral double",
+			with:        float64(42),
+			want:        42,
+		},
+		{
+			description: "Overflowed sum",
+			with:        1e19,
+			wantErr:     true,
+		},
+		{
+			description: "Null",
+			with:        nil,
+			want:        0,
+		},
+		{
+			description: "String",
+			with:        "42",
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := ToInt64(test.with)
+
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%s -> Got error %v, expected an error: %t", test.description, err, test.wantErr)
+		}
+
+		if result != test.want {
+			t.Fatalf("%s -> Got %d but expected %d", test.description, result, test.want)
+		}
+	}
+}
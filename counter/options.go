@@ -0,0 +1,98 @@
+package counter
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/remychantenay/fuego/internal/logging"
+	"github.com/remychantenay/fuego/internal/ratelimit"
+	"github.com/remychantenay/fuego/internal/retry"
+)
+
+// Option configures a FirestoreCounter.
+type Option func(*options)
+
+// RetryPolicy describes how failed operations are retried (see fuego.RetryPolicy).
+type RetryPolicy = retry.Policy
+
+// Limiter throttles operations.
+type Limiter interface {
+
+	// Wait blocks until n operations are allowed or ctx is done.
+	Wait(ctx context.Context, n int) error
+}
+
+// WithLogger sets the logger used by the counter (nothing is logged by default).
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.slog = l
+	}
+}
+
+// WithLogRedaction sets how field values appear in the logs (all of them are redacted by default).
+func WithLogRedaction(r func(field string, value interface{}) interface{}) Option {
+	return func(o *options) {
+		o.redact = r
+	}
+}
+
+// WithRetryPolicies sets the function returning the retry policy applying to a given operation (e.g. "Counter.Value").
+//
+// By default, failed operations are not retried.
+func WithRetryPolicies(fn func(op string) RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = fn
+	}
+}
+
+// WithLimiter sets the limiter throttling the reads and writes.
+func WithLimiter(l Limiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// options holds the client-wide settings used by a counter.
+//
+// A nil *options is valid and falls back to the defaults.
+type options struct {
+	slog    *slog.Logger
+	redact  logging.Redactor
+	log     *logging.Logger
+	retry   func(op string) RetryPolicy
+	limiter ratelimit.Limiter
+}
+
+// newOptions creates and returns options with the provided Option(s) applied.
+func newOptions(opts ...Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.log = logging.New(o.slog, o.redact)
+	return o
+}
+
+// logger returns the logger (if any), nil otherwise.
+func (o *options) logger() *logging.Logger {
+	if o == nil {
+		return nil
+	}
+	return o.log
+}
+
+// retryPolicy returns the retry policy applying to a given operation.
+func (o *options) retryPolicy(op string) retry.Policy {
+	if o == nil || o.retry == nil {
+		return retry.Policy{}
+	}
+	return o.retry(op)
+}
+
+// rateLimiter returns the limiter throttling the reads and writes (if any), nil otherwise.
+func (o *options) rateLimiter() ratelimit.Limiter {
+	if o == nil {
+		return nil
+	}
+	return o.limiter
+}
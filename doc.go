@@ -10,5 +10,76 @@ Create a fuego client:
 
 Note: firestoreClient needs to be created beforehand.
 
+Transactions
+
+The documents obtained from the Fuego passed to RunTransaction are part of the transaction:
+
+	err := fuegoClient.RunTransaction(ctx, func(ctx context.Context, tx *fuego.Fuego) error {
+		age, err := tx.Document("users", "jsmith").Number("Age").Retrieve(ctx)
+		if err != nil {
+			return err
+		}
+		return tx.Document("users", "jsmith").Merge(ctx, map[string]interface{}{"Adult": age >= 18})
+	})
+
+Counters
+
+Counters spread their value over several shard documents, to sustain high write rates (see the counter package):
+
+	err := fuegoClient.Counter("posts", "123", 10).Increment(ctx, 1)
+
+Logging
+
+Fuego is silent by default. A *slog.Logger can be provided to trace what happens:
+
+	fuegoClient := fuego.New(firestoreClient,
+		fuego.WithLogger(logger),
+		fuego.WithLogRedaction(fuego.RedactFields("Tokens")),
+	)
+
+Operations are logged at debug level, batch commits and transaction retries at info level
+and failures at error level. Field values are redacted unless specified otherwise with WithLogRedaction.
+
+Retries
+
+By default, failed operations are not retried. A retry policy (exponential backoff with jitter) can be configured,
+globally or per operation:
+
+	fuegoClient := fuego.New(firestoreClient,
+		fuego.WithRetryPolicy(fuego.DefaultRetryPolicy),
+		fuego.WithOperationRetryPolicy("Collection.DeleteAll", fuego.RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second}),
+	)
+
+The policy applies to direct writes and reads, to each batch commit of SetForAll and DeleteAll as well as to CommitBatch.
+Non-idempotent operations (transactions, CommitBatch) are only retried when the failure guarantees
+that nothing has been written (i.e. ResourceExhausted and Aborted).
+
+Errors
+
+The errors returned by fuego (incl. the document and collection packages) are *fuego.Error values.
+They carry the operation, the document path, the field name (if any) and a kind classifying the error:
+
+	err := fuegoClient.Document("users", "jsmith").Retrieve(ctx, &user)
+	if errors.Is(err, fuego.ErrNotFound) { // or errors.Is(err, document.ErrDocumentNotExist)
+		// ...
+	}
+
+	var fe *fuego.Error
+	if errors.As(err, &fe) && fe.Kind == fuego.Unavailable {
+		// ...
+	}
+
+Rate Limiting
+
+Reads and writes can be throttled client-side (token bucket), globally and/or per collection.
+Bulk operations (SetForAll, DeleteAll) can also follow Firestore's 500/50/5 ramp-up rule
+(start at 500 operations per second, then increase the traffic by 50% every 5 minutes):
+
+	fuegoClient := fuego.New(firestoreClient,
+		fuego.WithRateLimit(1000, 100),
+		fuego.WithCollectionRateLimit("users", 200, 20),
+		fuego.WithRampUp(),
+	)
+
 */
 package fuego
@@ -2,6 +2,8 @@ package document
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 
 	"cloud.google.com/go/firestore"
 	"github.com/remychantenay/fuego/document/internal"
@@ -18,9 +20,35 @@ type ArrayField interface {
 	// Append will append the provided data to the existing data (if any) of an Array field.
 	Append(ctx context.Context, data []interface{}) error
 
-	// Override will override the existing data (if any) of an Array field.
-	// Note: this is the default behaviour with Firestore.
-	Override(ctx context.Context, data []interface{}) error
+	// Override will replace the existing data (if any) of an Array field.
+	Override(ctx context.Context, data []interface{}, opts ...WriteOption) error
+
+	// Union adds the provided items which are not already part of an Array field.
+	Union(ctx context.Context, items []interface{}, opts ...WriteOption) error
+
+	// Remove removes all the occurrences of the provided items from an Array field.
+	Remove(ctx context.Context, items []interface{}, opts ...WriteOption) error
+
+	// Push adds the provided items to an Array field, keeping at most maxLen items.
+	Push(ctx context.Context, items []interface{}, maxLen int, opts ...PushOption) error
+
+	// InsertAt inserts the provided items at a given index of an Array field.
+	InsertAt(ctx context.Context, index int, items []interface{}) error
+
+	// RemoveAt removes the item at a given index of an Array field.
+	RemoveAt(ctx context.Context, index int) error
+
+	// RemoveWhere removes the items of an Array field matching a predicate and returns the number of items removed.
+	RemoveWhere(ctx context.Context, fn func(item interface{}) bool) (int, error)
+
+	// Contains returns true if an Array field contains the provided item.
+	Contains(ctx context.Context, item interface{}) (bool, error)
+
+	// Len returns the number of items of an Array field.
+	Len(ctx context.Context) (int, error)
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
 }
 
 // Array represents a document field of type Array.
@@ -33,64 +61,201 @@ type Array struct {
 	Name string
 
 	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *Array) field() *TypedField[[]interface{}] {
+	return &TypedField[[]interface{}]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
 }
 
 // Retrieve returns the content of a specific field for a given document.
 //  values, err := fuego.Document("users", "jsmith").Array("Address").Retrieve(ctx)
 func (f *Array) Retrieve(ctx context.Context) ([]interface{}, error) {
-	value, err := internal.RetrieveFieldValue(ctx, f.Document.GetDocumentRef(), f.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	return value.([]interface{}), nil
+	return f.field().get(ctx, "Array.Retrieve")
 }
 
-// Override will override the existing data (if any) of an Array field.
+// Override replaces the existing data (if any) of an Array field, the other fields of the document are left untouched.
 //  values, err := fuego.Document("users", "jsmith").Array("Address").Override(ctx, []interface{}{"New Street", "New Building"})
-func (f *Array) Override(ctx context.Context, data []interface{}) error {
-
-	ref := f.Document.GetDocumentRef()
-	m := map[string]interface{}{
-		f.Name: data,
-	}
-
-	if f.Document.InBatch() {
-		f.Document.Batch().Set(ref, m, firestore.MergeAll)
-		return nil
-	}
-
-	_, err := ref.Set(ctx, m, firestore.MergeAll)
-	return err
+func (f *Array) Override(ctx context.Context, data []interface{}, opts ...WriteOption) error {
+	return f.field().set(ctx, "Array.Override", data, opts...)
 }
 
 // Append will append the provided data to the existing data (if any) of an Array field.
 //
 // The update will be executed inside a transaction.
+// A NotFound error is returned if the field doesn't exist.
 //  values, err := fuego.Document("users", "jsmith").Array("Address").Append(ctx, []interface{}{"More info"})
 func (f *Array) Append(ctx context.Context, data []interface{}) error {
+	return f.field().modify(ctx, "Array.Append", func(current []interface{}, exists bool) ([]interface{}, bool, error) {
+		if !exists {
+			return nil, false, &firestore.FieldNotFoundError{Path: f.Name}
+		}
+		return append(current, data...), true, nil
+	})
+}
+
+// Union adds the provided items which are not already part of a specific field of type Array, at its end.
+//
+// The update is applied by Firestore: no transaction is needed, which also makes it usable in a write batch.
+// If the field doesn't exist, it will be set to the provided items.
+//  err := fuego.Document("users", "jsmith").Array("Tags").Union(ctx, []interface{}{"premium", "beta"})
+func (f *Array) Union(ctx context.Context, items []interface{}, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Array.Union", f.Document, f.Name, firestore.ArrayUnion(items...), opts...)
+}
+
+// Remove removes all the occurrences of the provided items from a specific field of type Array.
+//
+// The update is applied by Firestore: no transaction is needed, which also makes it usable in a write batch.
+// If the field doesn't exist, it will be set to an empty array.
+//  err := fuego.Document("users", "jsmith").Array("Tags").Remove(ctx, []interface{}{"beta"})
+func (f *Array) Remove(ctx context.Context, items []interface{}, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Array.Remove", f.Document, f.Name, firestore.ArrayRemove(items...), opts...)
+}
+
+// PushOption configures Array.Push.
+type PushOption func(*pushOptions)
+
+// PushFront adds the items at the front of the array, the items beyond the max. length being removed from its back.
+//  err := fuego.Document("users", "jsmith").Array("RecentlyViewed").Push(ctx, []interface{}{productID}, 10, document.PushFront)
+var PushFront PushOption = func(o *pushOptions) {
+	o.front = true
+}
+
+// DedupeBy deduplicates the items of the array by key: an item already in the array is moved instead of being added twice.
+//
+// key is given the items read from Firestore (e.g. maps for structs) as well as the provided ones.
+// The keys which can't be map keys (e.g. maps or slices) are compared with reflect.DeepEqual.
+//  product := map[string]interface{}{"ID": "123", "Name": "Guitar"}
+//  err := fuego.Document("users", "jsmith").Array("RecentlyViewed").Push(ctx, []interface{}{product}, 10,
+//  	document.DedupeBy(func(item interface{}) interface{} {
+//  		return item.(map[string]interface{})["ID"]
+//  	}),
+//  )
+func DedupeBy(key func(item interface{}) interface{}) PushOption {
+	return func(o *pushOptions) {
+		o.key = key
+	}
+}
+
+// pushOptions holds the settings of Array.Push.
+type pushOptions struct {
+	front bool
+	key   func(item interface{}) interface{}
+}
 
-	return f.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+// Push adds the provided items at the end of a specific field of type Array (see PushFront),
+// the items beyond maxLen (at least 1) being removed from its front.
+//
+// The update will be executed inside a transaction, the key given to DedupeBy (if any) may be called more than once.
+// If the field doesn't exist, it will be set to the provided items (the last maxLen ones).
+// An InvalidArgument error wrapping ErrInvalidLength is returned if maxLen is lower than 1.
+//  err := fuego.Document("users", "jsmith").Array("RecentlyViewed").Push(ctx, []interface{}{productID}, 10)
+func (f *Array) Push(ctx context.Context, items []interface{}, maxLen int, opts ...PushOption) error {
+	if maxLen < 1 {
+		op := operation{name: "Array.Push", ref: f.Document.GetDocumentRef(), field: f.Name}
+		return f.opts.fail(ctx, op, fmt.Errorf("%w: %d", ErrInvalidLength, maxLen), op.attrs()...)
+	}
+
+	o := pushOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return f.field().modify(ctx, "Array.Push", func(current []interface{}, exists bool) ([]interface{}, bool, error) {
+		return internal.Push(current, items, maxLen, o.front, o.key), true, nil
+	})
+}
 
-		document, err := tx.Get(f.Document.GetDocumentRef())
-		if err != nil {
-			return err
+// InsertAt inserts the provided items at a given index (0 to the length of the array) of a specific field of type Array.
+//
+// The update will be executed inside a transaction.
+// If the field doesn't exist, it will be set to the provided items (the index has to be 0).
+// An InvalidArgument error wrapping ErrIndexOutOfRange is returned if the index is out of range.
+//  err := fuego.Document("users", "jsmith").Array("Address").InsertAt(ctx, 1, []interface{}{"2nd Floor"})
+func (f *Array) InsertAt(ctx context.Context, index int, items []interface{}) error {
+	return f.field().modify(ctx, "Array.InsertAt", func(current []interface{}, exists bool) ([]interface{}, bool, error) {
+		if index < 0 || index > len(current) {
+			return nil, false, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, index, len(current))
 		}
+		return internal.InsertAt(current, index, items...), true, nil
+	})
+}
 
-		value, err := document.DataAt(f.Name)
-		if err != nil {
-			return err
+// RemoveAt removes the item at a given index of a specific field of type Array.
+//
+// The update will be executed inside a transaction.
+// An InvalidArgument error wrapping ErrIndexOutOfRange is returned if the index is out of range
+// (which is always the case if the field doesn't exist).
+//  err := fuego.Document("users", "jsmith").Array("Address").RemoveAt(ctx, 0)
+func (f *Array) RemoveAt(ctx context.Context, index int) error {
+	return f.field().modify(ctx, "Array.RemoveAt", func(current []interface{}, exists bool) ([]interface{}, bool, error) {
+		if index < 0 || index >= len(current) {
+			return nil, false, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, index, len(current))
 		}
+		return internal.RemoveAt(current, index), true, nil
+	})
+}
 
-		value = append(value.([]interface{}), data...)
+// RemoveWhere removes the items of a specific field of type Array matching a predicate
+// and returns the number of items removed.
+//
+// The update will be executed inside a transaction, fn may be called more than once per item.
+// Nothing is written if no item matches (e.g. if the field doesn't exist).
+//  removed, err := fuego.Document("users", "jsmith").Array("Tags").RemoveWhere(ctx, func(item interface{}) bool {
+//  	return strings.HasPrefix(item.(string), "tmp-")
+//  })
+func (f *Array) RemoveWhere(ctx context.Context, fn func(item interface{}) bool) (int, error) {
+	removed := 0
+	err := f.field().modify(ctx, "Array.RemoveWhere", func(current []interface{}, exists bool) ([]interface{}, bool, error) {
+		var next []interface{}
+		next, removed = internal.RemoveWhere(current, fn)
+		return next, removed > 0, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+// Contains returns true if a specific field of type Array contains the provided item, false if the field doesn't exist.
+//
+// Items are compared with reflect.DeepEqual once decoded (e.g. integers are int64, maps are map[string]interface{}).
+//  premium, err := fuego.Document("users", "jsmith").Array("Tags").Contains(ctx, "premium")
+func (f *Array) Contains(ctx context.Context, item interface{}) (bool, error) {
+	values, _, err := f.field().lookup(ctx, "Array.Contains")
+	if err != nil {
+		return false, err
+	}
 
-		err = tx.Set(f.Document.GetDocumentRef(), map[string]interface{}{
-			f.Name: value,
-		}, firestore.MergeAll)
-		if err != nil {
-			return err
+	for _, v := range values {
+		if reflect.DeepEqual(v, item) {
+			return true, nil
 		}
+	}
+	return false, nil
+}
 
-		return nil // Success, no errors
-	})
+// Len returns the number of items of a specific field of type Array, 0 if the field doesn't exist.
+//  count, err := fuego.Document("users", "jsmith").Array("Tags").Len(ctx)
+func (f *Array) Len(ctx context.Context) (int, error) {
+	values, _, err := f.field().lookup(ctx, "Array.Len")
+	if err != nil {
+		return 0, err
+	}
+
+	return len(values), nil
+}
+
+// Delete removes a specific field of type Array from the document.
+//  err := fuego.Document("users", "jsmith").Array("Address").Delete(ctx)
+func (f *Array) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Array.Delete", f.Document, f.Name, Delete, opts...)
 }
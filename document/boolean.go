@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"cloud.google.com/go/firestore"
-	"github.com/remychantenay/fuego/document/internal"
 )
 
 // BooleanField provides the necessary to interact with a Firestore document field of type Boolean.
@@ -14,7 +13,17 @@ type BooleanField interface {
 	Retrieve(ctx context.Context) (bool, error)
 
 	// Update updates the value of a specific field containing a Boolean (bool).
-	Update(ctx context.Context, with bool) error
+	Update(ctx context.Context, with bool, opts ...WriteOption) error
+
+	// Toggle flips the value of a specific field containing a Boolean (bool) and returns the new value.
+	Toggle(ctx context.Context) (bool, error)
+
+	// SetIf sets the value of a specific field containing a Boolean (bool) only if it currently holds the expected value,
+	// and returns true if it has been set.
+	SetIf(ctx context.Context, expected, with bool) (bool, error)
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
 }
 
 // Boolean represents a document field of type Boolean.
@@ -25,33 +34,67 @@ type Boolean struct {
 
 	// Name is the name of the field.
 	Name string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *Boolean) field() *TypedField[bool] {
+	return &TypedField[bool]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
 }
 
 // Retrieve returns the content of a specific field for a given document.
 //  val, err := fuego.Document("users", "jsmith").Boolean("Premium").Retrieve(ctx)
 func (f *Boolean) Retrieve(ctx context.Context) (bool, error) {
-	value, err := internal.RetrieveFieldValue(ctx, f.Document.GetDocumentRef(), f.Name)
-	if err != nil {
-		return false, err
-	}
-
-	return value.(bool), nil
+	return f.field().get(ctx, "Boolean.Retrieve")
 }
 
 // Update updates the value of a specific field of type Boolean.
 //  err := fuego.Document("users", "jsmith").Boolean("Premium").Update(ctx, true)
-func (f *Boolean) Update(ctx context.Context, with bool) error {
+func (f *Boolean) Update(ctx context.Context, with bool, opts ...WriteOption) error {
+	return f.field().set(ctx, "Boolean.Update", with, opts...)
+}
 
-	ref := f.Document.GetDocumentRef()
-	m := map[string]bool{
-		f.Name: with,
+// Toggle flips the value of a specific field of type Boolean and returns the new value.
+//
+// The update will be executed inside a transaction.
+// If the field doesn't exist, it will be set to true.
+//  premium, err := fuego.Document("users", "jsmith").Boolean("Premium").Toggle(ctx)
+func (f *Boolean) Toggle(ctx context.Context) (bool, error) {
+	var value bool
+	err := f.field().modify(ctx, "Boolean.Toggle", func(current bool, exists bool) (bool, bool, error) {
+		value = !current
+		return value, true, nil
+	})
+	if err != nil {
+		return false, err
 	}
 
-	if f.Document.InBatch() {
-		f.Document.Batch().Set(ref, m, firestore.MergeAll)
-		return nil
-	}
+	return value, nil
+}
+
+// SetIf sets the value of a specific field of type Boolean only if it currently holds the expected value,
+// and returns true if it has been set. A missing field is considered as holding false.
+//
+// The comparison and the update will be executed inside a transaction, which makes it suitable for one-shot claims:
+// only one of the concurrent callers gets true.
+//  claimed, err := fuego.Document("users", "jsmith").Boolean("WelcomeEmailSent").SetIf(ctx, false, true)
+//  if err == nil && claimed {
+//  	// send the welcome email
+//  }
+func (f *Boolean) SetIf(ctx context.Context, expected, with bool) (bool, error) {
+	return f.field().compareAndSwap(ctx, "Boolean.SetIf", expected, with)
+}
 
-	_, err := ref.Set(ctx, m, firestore.MergeAll)
-	return err
+// Delete removes a specific field of type Boolean from the document.
+//  err := fuego.Document("users", "jsmith").Boolean("Premium").Delete(ctx)
+func (f *Boolean) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Boolean.Delete", f.Document, f.Name, Delete, opts...)
 }
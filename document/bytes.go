@@ -0,0 +1,84 @@
+package document
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+)
+
+// BytesField provides the necessary to interact with a Firestore document field of type Bytes.
+type BytesField interface {
+
+	// Retrieve returns the value of a specific field containing bytes ([]byte).
+	Retrieve(ctx context.Context) ([]byte, error)
+
+	// Update updates the value of a specific field containing bytes ([]byte).
+	Update(ctx context.Context, with []byte, opts ...WriteOption) error
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
+}
+
+// Bytes represents a document field of type Bytes.
+type Bytes struct {
+
+	// Document is the underlying document (incl. ID and ref).
+	Document Document
+
+	// Name is the name of the field.
+	Name string
+
+	// MaxSize is the max. size of the values written to the field, in bytes (no limit if 0).
+	MaxSize int
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *Bytes) field() *TypedField[[]byte] {
+	return &TypedField[[]byte]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
+}
+
+// WithMaxSize returns a copy of the field which values can't exceed maxSize bytes (no limit if 0),
+// which is checked before writing.
+//  err := fuego.Document("users", "jsmith").Bytes("Avatar").WithMaxSize(64 << 10).Update(ctx, avatar)
+func (f *Bytes) WithMaxSize(maxSize int) *Bytes {
+	b := *f
+	b.MaxSize = maxSize
+	return &b
+}
+
+// Retrieve returns the content of a specific field for a given document.
+//  avatar, err := fuego.Document("users", "jsmith").Bytes("Avatar").Retrieve(ctx)
+func (f *Bytes) Retrieve(ctx context.Context) ([]byte, error) {
+	return f.field().get(ctx, "Bytes.Retrieve")
+}
+
+// Update updates the value of a specific field of type Bytes.
+//
+// An InvalidArgument error wrapping ErrSizeLimitExceeded is returned if the value exceeds the max. size of the field (if any).
+// Firestore doesn't accept values of more than about 1MiB in any case.
+//  err := fuego.Document("users", "jsmith").Bytes("Avatar").Update(ctx, avatar)
+func (f *Bytes) Update(ctx context.Context, with []byte, opts ...WriteOption) error {
+	if f.MaxSize > 0 && len(with) > f.MaxSize {
+		op := operation{name: "Bytes.Update", ref: f.Document.GetDocumentRef(), field: f.Name}
+		err := fmt.Errorf("%w: %d bytes (max. %d)", ErrSizeLimitExceeded, len(with), f.MaxSize)
+		return f.opts.fail(ctx, op, err, op.attrs()...)
+	}
+
+	return f.field().set(ctx, "Bytes.Update", with, opts...)
+}
+
+// Delete removes a specific field of type Bytes from the document.
+//  err := fuego.Document("users", "jsmith").Bytes("Avatar").Delete(ctx)
+func (f *Bytes) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Bytes.Delete", f.Document, f.Name, Delete, opts...)
+}
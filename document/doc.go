@@ -15,21 +15,48 @@ Creating a document is straightforward:
 		Tokens          map[string]string   `firestore:"Tokens"`
 	}
 
-	err := fuego.Document("users", "jsmith").Create(ctx, user)
+	_, err := fuego.Document("users", "jsmith").Create(ctx, user)
     if err != nil {
         panic(err)
     }
 
+Create fails with an AlreadyExists error if the document already exists. Upsert creates or overwrites it,
+Replace overwrites it and fails with a NotFound error if it doesn't exist, Merge merges the data with it:
+
+	_, err := fuego.Document("users", "jsmith").Upsert(ctx, user)
+	err := fuego.Document("users", "jsmith").Replace(ctx, user)
+	err := fuego.Document("users", "jsmith").Merge(ctx, user, document.Fields("FirstName", "LastName"))
+
 In some cases, you may want to create a document and let Firebase generated a unique ID for you.
-Fuego supports this:
+Fuego supports this and returns the generated ID:
+
+	id, err := fuego.DocumentWithGeneratedID("users").Create(ctx, user)
 
-	err := fuego.DocumentWithGeneratedID("users").Create(ctx, user)
+The generated ID is kept by the document (see FirestoreDocument.GetID). How IDs are generated
+can be configured on the client (e.g. fuego.WithIDStrategy(fuego.UUIDv7)).
 
 Retrieving a document as even simpler:
 
 	user := User{}
 	err := fuego.Document("users", "jsmith").Retrieve(ctx, &user)
 
+Optimistic concurrency
+
+A read-modify-write can be made safe without a transaction: the metadata of a document (incl. its update time)
+can be retrieved with it, and the write only applied if the document has not been updated since then.
+
+	user := User{}
+	meta, err := fuego.Document("users", "jsmith").RetrieveWithMeta(ctx, &user)
+
+	user.Address = "1 Main Street"
+	err = fuego.Document("users", "jsmith").Replace(ctx, user, document.IfUpdatedAt(meta.UpdateTime))
+	if errors.Is(err, document.ErrPreconditionFailed) {
+		// the document has been updated in the meantime...
+	}
+
+The preconditions (IfUpdatedAt, IfExists) are accepted by the writes of the documents and their fields
+and result in a Conflict error when not met.
+
 You also may want to only check if a given document exists without providing a struct:
 
 	// Note: false will be returned if an error occurs as well
@@ -38,7 +65,7 @@ You also may want to only check if a given document exists without providing a s
 Fields - All types
 
 Fuego also allow to easily manipulate specific fields of a document (e.g. retrieve, update, increment, ...)
-It supports all the fields offered by Firestore at the exception of geopoints and references.
+It supports all the fields offered by Firestore.
 
 Usage
 
@@ -50,6 +77,122 @@ Updating a field:
 
 	err := fuego.Document("users", "jsmith").String("FirstName").Update(ctx, "Mike")
 
+Deleting a field (all types, in a write batch or a transaction as well):
+
+	err := fuego.Document("users", "jsmith").String("FirstName").Delete(ctx)
+
+	// or only some keys of a map...
+	err := fuego.Document("users", "jsmith").Map("Tokens").DeleteKeys(ctx, []string{"Android", "IOS"})
+
+Updates only apply to existing documents (a NotFound error is returned otherwise).
+Nested fields are dot-separated and several fields can be updated in a single write:
+
+	err := fuego.Document("users", "jsmith").String("Address.City").Update(ctx, "Dublin")
+
+	err := fuego.Document("users", "jsmith").Update(ctx,
+		document.Update{Path: "Address.City", Value: "Dublin"},
+		document.Update{Path: "Tokens", Value: document.Delete},
+		document.Update{Path: "LastSeenAt", Value: document.ServerTimestamp},
+	)
+
+Fields - Types
+
+A field value of an unexpected type (e.g. a double retrieved as a Number) results in a TypeMismatch error
+mentioning the actual Firestore type. Lenient conversions can be enabled per client:
+
+	fuegoClient := fuego.New(firestoreClient, fuego.WithConversions(document.LenientConversions))
+
+	// or only some of them...
+	fuegoClient := fuego.New(firestoreClient, fuego.WithConversions(document.ConvertIntegralFloats|document.ConvertNullToZero))
+
+Fields - GeoPoints
+
+Geopoints are *latlng.LatLng values:
+
+	err := fuego.Document("shops", "123").GeoPoint("Entrance").Update(ctx, &latlng.LatLng{Latitude: 48.8584, Longitude: 2.2945})
+	point, err := fuego.Document("shops", "123").GeoPoint("Entrance").Retrieve(ctx)
+
+To be queried by distance, a location has to be stored with its geohash (see the geo package).
+
+Fields - Bytes, References and Nulls
+
+Bytes can be limited to a max. size, checked before writing (an InvalidArgument error wrapping ErrSizeLimitExceeded
+being returned if exceeded):
+
+	err := fuego.Document("users", "jsmith").Bytes("Avatar").WithMaxSize(64 << 10).Update(ctx, avatar)
+
+A reference is retrieved as a document, which can be used directly:
+
+	err := fuego.Document("users", "jsmith").Reference("Manager").Update(ctx, fuego.Document("users", "enorton"))
+
+	manager, err := fuego.Document("users", "jsmith").Reference("Manager").Retrieve(ctx)
+	err = manager.Retrieve(ctx, &user)
+
+Unlike Delete, SetNull keeps the field (set to null). IsNull tells a null field apart from a missing one:
+
+	err := fuego.Document("users", "jsmith").SetNull(ctx, "MiddleName")
+	null, err := fuego.Document("users", "jsmith").IsNull(ctx, "MiddleName") // true
+
+Fields - Generic
+
+Fields of any type Firestore can encode (incl. structs, slices and maps) can be manipulated with Field:
+
+	type Address struct {
+		Street string `firestore:"Street"`
+		City   string `firestore:"City"`
+	}
+
+	field := document.Field[Address](fuego.Document("users", "jsmith"), "Address")
+
+	address, err := field.Get(ctx)
+	err = field.Set(ctx, Address{Street: "1 Main Street", City: "Dublin"})
+	err = field.Delete(ctx)
+
+Values of types other than the basic ones (e.g. structs) are decoded by Firestore, which doesn't support
+keys containing a comma or equal to "-" in their path: an InvalidArgument error is returned when retrieving them.
+
+CompareAndSwap updates a field only if it holds an expected value (inside a transaction):
+
+	swapped, err := document.Field[string](fuego.Document("orders", "123"), "Status").CompareAndSwap(ctx, "pending", "processing")
+
+Watch calls a function with the value of a field every time it changes, until the context is done:
+
+	err := document.Field[string](fuego.Document("orders", "123"), "Status").Watch(ctx, func(status string, exists bool) error {
+		fmt.Println("Status: ", status)
+		return nil
+	})
+
+Arrays of any type can be manipulated with ArrayOf, which provides the same operations as Array with typed items:
+
+	addresses := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses")
+
+	values, err := addresses.Retrieve(ctx) // []Address
+	err = addresses.Append(ctx, []Address{{Street: "1 Main Street", City: "Dublin"}}) // the field has to exist
+
+Fields - Maps
+
+The keys of a map can be read and updated one by one, as any other field (keys containing dots or backticks are supported):
+
+	city, err := fuego.Document("users", "jsmith").Map("Address").Key("City").String().Retrieve(ctx)
+
+	err := fuego.Document("users", "jsmith").Map("Settings").Key("notifications").Key("email").Boolean().Update(ctx, true)
+
+Merge sets the given keys and leaves the others untouched, nested maps being merged key by key
+(unless the Shallow option is given), whereas Override replaces the whole map:
+
+	err := fuego.Document("users", "jsmith").Map("Settings").Merge(ctx, map[string]interface{}{
+		"Notifications": map[string]interface{}{"Push": false}, // "Notifications.Email" is kept
+	})
+
+	err := fuego.Document("users", "jsmith").Map("Tokens").Override(ctx, map[string]interface{}{"Android": "AND_123"})
+
+Field names are paths as well: nested keys are separated by dots and keys can be quoted with backticks
+(e.g. "Tokens.`web.app`"), backticks and backslashes being escaped with a backslash.
+
+Note that this is a breaking change for the top-level fields whose name contains a dot: String("a.b") used to
+read and write the field literally named "a.b", it now reads and writes the key b of the map a.
+Quote the name with backticks (i.e. String("`a.b`")) to keep accessing the top-level field.
+
 Fields - Numbers
 
 Numbers are stored in Firestore as int64. Fuego provides operations that are frequently performed with number fields.
@@ -61,6 +204,17 @@ With Fuego, this can be done in one single operatipn. It uses Transactions under
 	// or
 	err := fuego.Document("users", "ben_button").Number(Age).Decrement(ctx)
 
+Add, Min and Max are applied by Firestore (no transaction, usable in a write batch), Multiply uses a transaction.
+Increment and Decrement keep using a transaction (Decrement sets a missing field to 0): use Add(ctx, 1) or Add(ctx, -1)
+to have the update applied by Firestore instead:
+
+	err := fuego.Document("users", "jsmith").Number("Points").Add(ctx, 10)
+	err := fuego.Document("users", "jsmith").Number("Lives").Min(ctx, 3) // caps the value
+
+Float provides the same operations for doubles (float64):
+
+	err := fuego.Document("users", "jsmith").Float("Balance").Add(ctx, -12.5)
+
 Fields - Arrays
 
 As you may know, dealing with arrays in Firestore documents can be somewhat of a burden.
@@ -87,12 +241,83 @@ Of course, you can also retrieve an array as follow:
 	// Note the required type assertion
 	fmt.Println("First Element: ", values[0].(string))
 
+Union and Remove add (if not already present) and remove items without a transaction, they are usable in a write batch:
+
+	err := fuego.Document("users", "jsmith").Array("Tags").Union(ctx, []interface{}{"premium"})
+
+InsertAt, RemoveAt and RemoveWhere use a transaction. As Contains and Len, they work when the field doesn't exist yet:
+
+	err := fuego.Document("users", "jsmith").Array("Address").InsertAt(ctx, 0, []interface{}{"Apartment 5"})
+
+	removed, err := fuego.Document("users", "jsmith").Array("Tags").RemoveWhere(ctx, func(item interface{}) bool {
+		return item == "beta"
+	})
+
+Push adds items and keeps the array capped (e.g. a "recently viewed" list), re-pushed items being moved with DedupeBy:
+
+	product := map[string]interface{}{"ID": "123", "Name": "Guitar"}
+	err := fuego.Document("users", "jsmith").Array("RecentlyViewed").Push(ctx, []interface{}{product}, 10,
+		document.DedupeBy(func(item interface{}) interface{} { return item.(map[string]interface{})["ID"] }),
+	)
+
+Fields - Strings
+
+CompareAndSwap and UpdateIfIn only update a string if it holds an expected value (e.g. to transition a status),
+inside a transaction:
+
+	swapped, err := fuego.Document("orders", "123").String("Status").CompareAndSwap(ctx, "pending", "processing")
+
+	updated, err := fuego.Document("orders", "123").String("Status").UpdateIfIn(ctx, []string{"pending", "processing"}, "cancelled")
+
+Fields - States
+
+A State field holds a state which only changes according to declared transitions (a missing field being in the "" state).
+Transition writes the new state and an entry of the transition history atomically, illegal moves resulting in
+a Conflict error wrapping a *TransitionError:
+
+	transitions := document.Transitions{
+		"":           {"pending"},
+		"pending":    {"processing", "cancelled"},
+		"processing": {"shipped", "cancelled"},
+	}
+
+	status := fuego.Document("orders", "123").State("Status", transitions)
+	err := status.Transition(ctx, "processing")
+
+	var transitionErr *document.TransitionError
+	if errors.As(err, &transitionErr) {
+		fmt.Println("Can't move from ", transitionErr.From)
+	}
+
+	history, err := status.History(ctx) // stored in "StatusHistory"
+
+The history isn't capped: each transition adds an entry, within the max. size of a document (1 MiB).
+
+Fields - Booleans
+
+Toggle flips a boolean and SetIf only sets it if it holds the expected value (e.g. for one-shot claims),
+both inside a transaction:
+
+	premium, err := fuego.Document("users", "jsmith").Boolean("Premium").Toggle(ctx)
+
+	claimed, err := fuego.Document("users", "jsmith").Boolean("WelcomeEmailSent").SetIf(ctx, false, true)
+
 Fields - Timestamp
 
 Timestamp fields work the same way as the other fields except that a timezone (IANA Time Zone)
-needs to be provided at retrieval:
+needs to be provided at retrieval (the locations are cached by the client):
 
 	val, err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").Retrieve(ctx, "America/Los_Angeles")
 
+	// or...
+	val, err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").RetrieveIn(ctx, loc)
+	val, err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").RetrieveUTC(ctx)
+
+Touch sets a timestamp to the server time (which doesn't depend on the clock of the client) and returns it:
+
+	lastSeenAt, err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").Touch(ctx)
+
+In a write batch or a transaction, the zero time is returned: the time is only known once committed.
+
 */
 package document
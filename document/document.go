@@ -3,28 +3,59 @@ package document
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"cloud.google.com/go/firestore"
+	"github.com/remychantenay/fuego/internal/errs"
+	"github.com/remychantenay/fuego/internal/retry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Document provides the necessary to interact with a Firestore document.
 type Document interface {
 
-	// Create a document.
-	Create(ctx context.Context, from interface{}) error
+	// Create creates a document and returns its ID.
+	//
+	// note: an AlreadyExists error is returned if the document already exists.
+	Create(ctx context.Context, from interface{}) (string, error)
+
+	// Upsert creates or overwrites a document and returns its ID.
+	Upsert(ctx context.Context, from interface{}, opts ...WriteOption) (string, error)
+
+	// Replace overwrites an existing document.
+	//
+	// note: a NotFound error is returned if the document doesn't exist.
+	Replace(ctx context.Context, from interface{}, opts ...WriteOption) error
+
+	// Merge merges the provided data with the existing document (if any).
+	//
+	// note: only the given fields are merged (see Fields), all of them otherwise.
+	Merge(ctx context.Context, from interface{}, opts ...WriteOption) error
+
+	// Update applies updates to the fields of an existing document, in a single write.
+	//
+	// note: a NotFound error is returned if the document doesn't exist.
+	Update(ctx context.Context, updates ...Update) error
 
 	// Retrieve populate the destination passed as parameter.
 	//
 	// note: the `to` parameter has to be a pointer.
 	Retrieve(ctx context.Context, to interface{}) error
 
+	// RetrieveWithMeta populate the destination passed as parameter and returns the metadata of the document.
+	//
+	// note: the `to` parameter has to be a pointer.
+	RetrieveWithMeta(ctx context.Context, to interface{}) (Meta, error)
+
 	// Exists returns true if the document exists, false otherwise.
 	//
 	// note: if an error occurs, false is also returned.
 	Exists(ctx context.Context) bool
 
 	// Delete removes a document from Firestore.
-	Delete(ctx context.Context) error
+	Delete(ctx context.Context, opts ...WriteOption) error
 
 	// Array returns a specific Array field.
 	Array(name string) *Array
@@ -35,6 +66,9 @@ type Document interface {
 	// Number returns a specific Number field.
 	Number(name string) *Number
 
+	// Float returns a specific Number field holding doubles.
+	Float(name string) *Float
+
 	// Boolean returns a specific Boolean field.
 	Boolean(name string) *Boolean
 
@@ -44,6 +78,24 @@ type Document interface {
 	// Timestamp returns a specific Timestamp field.
 	Timestamp(name string) *Timestamp
 
+	// GeoPoint returns a specific GeoPoint field.
+	GeoPoint(name string) *GeoPoint
+
+	// Bytes returns a specific Bytes field.
+	Bytes(name string) *Bytes
+
+	// Reference returns a specific Reference field.
+	Reference(name string) *Reference
+
+	// IsNull returns true if a specific field holds null, false if it holds another value or doesn't exist.
+	IsNull(ctx context.Context, name string) (bool, error)
+
+	// SetNull sets a specific field to null.
+	SetNull(ctx context.Context, name string, opts ...WriteOption) error
+
+	// State returns a specific String field holding a state, which only changes according to the given transitions.
+	State(name string, transitions Transitions) *State
+
 	// GetDocumentRef returns a Document Reference (DocumentRef).
 	GetDocumentRef() *firestore.DocumentRef
 
@@ -52,6 +104,12 @@ type Document interface {
 
 	// Batch returns the pointer to the WriteBatch (if any), nil oherwise.
 	Batch() *firestore.WriteBatch
+
+	// InTransaction returns true if the document is part of a transaction, false otherwise.
+	InTransaction() bool
+
+	// Transaction returns the pointer to the Transaction (if any), nil otherwise.
+	Transaction() *firestore.Transaction
 }
 
 // FirestoreDocument provides features related to Firestore documents.
@@ -61,27 +119,48 @@ type FirestoreDocument struct {
 	ColRef *firestore.CollectionRef
 
 	// ID is the ID of the document
+	//
+	// note: when not provided, it is generated once (by the ID strategy) and kept. As it may be allocated
+	// by a write, it has to be read with GetID when the document is shared between goroutines.
 	ID string
 
+	// mu guards ID, which may be allocated by a write.
+	mu sync.Mutex
+
 	// writeBatch will be nil if not started with fuego.StartBatch() or cancelled with fuego.CancelBatch().
 	writeBatch *firestore.WriteBatch
 
 	firestore *firestore.Client
+
+	opts *options
 }
 
 // New creates and returns a new FirestoreDocument.
-func New(fs *firestore.Client, path, documentID string, wb *firestore.WriteBatch) *FirestoreDocument {
+func New(fs *firestore.Client, path, documentID string, wb *firestore.WriteBatch, opts ...Option) *FirestoreDocument {
 	r := fs.Collection(path)
-	return &FirestoreDocument{
+	d := &FirestoreDocument{
 		ColRef:     r,
 		ID:         documentID,
 		firestore:  fs,
 		writeBatch: wb,
+		opts:       newOptions(opts...),
 	}
+
+	if len(d.ID) == 0 {
+		// Strategies deriving the ID from the data fail here, the ID is then allocated at the first write.
+		d.ID, _ = d.opts.idStrategy()(nil)
+	}
+
+	return d
 }
 
 // GetDocumentRef returns a document reference.
+//
+// note: if the ID is derived from the data and the document hasn't been written yet,
+// a reference to a new document (with a Firestore generated ID) is returned.
 func (d *FirestoreDocument) GetDocumentRef() *firestore.DocumentRef {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
 	if len(d.ID) == 0 {
 		return d.ColRef.NewDoc()
@@ -90,60 +169,336 @@ func (d *FirestoreDocument) GetDocumentRef() *firestore.DocumentRef {
 	return d.ColRef.Doc(d.ID)
 }
 
-// Create a document in Firestore.
-func (d *FirestoreDocument) Create(ctx context.Context, from interface{}) error {
-	ref := d.GetDocumentRef()
-	if d.InBatch() {
-		d.Batch().Set(ref, from)
-		return nil
+// GetID returns the ID of the document, empty if it is derived from the data and the document hasn't been written yet.
+func (d *FirestoreDocument) GetID() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.ID
+}
+
+// writeOperation returns the operation writing data to the document, allocating the ID of the document if needed.
+func (d *FirestoreDocument) writeOperation(ctx context.Context, name string, data interface{}) (operation, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.ID) == 0 {
+		id, err := d.opts.idStrategy()(data)
+		if err != nil {
+			err = errs.New(name, d.ColRef.Path, "", errs.InvalidArgument, err)
+			d.opts.logger().Failure(ctx, name, d.ColRef.Path, err)
+			return operation{}, err
+		}
+		d.ID = id
 	}
-	_, err := ref.Set(ctx, from)
-	return err
+
+	return operation{name: name, ref: d.ColRef.Doc(d.ID)}, nil
 }
 
-// Retrieve a document from Firestore.
+// Create creates a document in Firestore and returns its ID.
 //
-// to: the destination must be a pointer.
-func (d *FirestoreDocument) Retrieve(ctx context.Context, to interface{}) error {
-	s, err := d.GetDocumentRef().Get(ctx)
+// An AlreadyExists error is returned if the document already exists.
+// In a write batch or a transaction, the whole batch or transaction will fail.
+//  id, err := fuego.Document("users", "jsmith").Create(ctx, user)
+func (d *FirestoreDocument) Create(ctx context.Context, from interface{}) (string, error) {
+	op, err := d.writeOperation(ctx, "Document.Create", from)
 	if err != nil {
-		return err
+		return "", err
+	}
+	op.idem = retry.NonIdempotent
+
+	err = d.opts.write(ctx, d, op, writer{
+		direct: func(ctx context.Context) error {
+			_, err := op.ref.Create(ctx, from)
+			return err
+		},
+		batch: func(wb *firestore.WriteBatch) error {
+			wb.Create(op.ref, from)
+			return nil
+		},
+		tx: func(tx *firestore.Transaction) error {
+			return tx.Create(op.ref, from)
+		},
+	}, d.opts.logger().Value("", from))
+	if err != nil {
+		return "", err
 	}
 
-	if !s.Exists() {
-		return ErrDocumentNotExist
+	return op.ref.ID, nil
+}
+
+// Upsert creates or overwrites a document in Firestore and returns its ID.
+//
+// Preconditions (e.g. IfUpdatedAt) can be provided, a Conflict error is returned if they are not met.
+//  id, err := fuego.Document("users", "jsmith").Upsert(ctx, user)
+func (d *FirestoreDocument) Upsert(ctx context.Context, from interface{}, opts ...WriteOption) (string, error) {
+	w := newWriteOptions(opts...)
+	op, err := d.writeOperation(ctx, "Document.Upsert", from)
+	if err != nil {
+		return "", err
+	}
+	op.conditional = w.conditional()
+
+	if !w.conditional() {
+		err = d.opts.write(ctx, d, op, writer{
+			direct: func(ctx context.Context) error {
+				_, err := op.ref.Set(ctx, from)
+				return err
+			},
+			batch: func(wb *firestore.WriteBatch) error {
+				wb.Set(op.ref, from)
+				return nil
+			},
+			tx: func(tx *firestore.Transaction) error {
+				return tx.Set(op.ref, from)
+			},
+		}, d.opts.logger().Value("", from))
+	} else {
+		err = d.opts.write(ctx, d, op, d.replacer(op, from, w, false), d.opts.logger().Value("", from))
+	}
+	if err != nil {
+		return "", err
 	}
 
-	if err := s.DataTo(to); err != nil {
+	return op.ref.ID, nil
+}
+
+// Replace overwrites an existing document in Firestore.
+//
+// A NotFound error is returned if the document doesn't exist.
+// Preconditions (e.g. IfUpdatedAt) can be provided, a Conflict error is returned if they are not met.
+// In a write batch or a transaction, the document is deleted and recreated, which resets its creation time.
+// The deletion requires the document to exist: if it doesn't, the whole batch or transaction fails when committed.
+//  err := fuego.Document("users", "jsmith").Replace(ctx, user)
+func (d *FirestoreDocument) Replace(ctx context.Context, from interface{}, opts ...WriteOption) error {
+	w := newWriteOptions(opts...)
+	op, err := d.writeOperation(ctx, "Document.Replace", from)
+	if err != nil {
 		return err
 	}
+	op.conditional = w.conditional()
 
-	return nil
+	// The document has to exist, which is checked before the given preconditions so that a NotFound error
+	// (rather than a Conflict error) is returned if it doesn't.
+	return d.opts.write(ctx, d, op, d.replacer(op, from, w, true), d.opts.logger().Value("", from))
 }
 
-// Exists returns true if a given document exists, false otherwise.
-func (d *FirestoreDocument) Exists(ctx context.Context) bool {
-	s, err := d.GetDocumentRef().Get(ctx)
-	if err != nil || !s.Exists() {
-		return false
+// replacer returns the writer overwriting a document if it meets the preconditions of a write.
+//
+// The preconditions are checked within a transaction when the write is direct. Otherwise, they are applied
+// to the deletion of the document (which then fails the batch or the transaction if they are not met),
+// followed by its recreation.
+//
+// If mustExist is true, ErrDocumentNotExist is returned (directly) if the document doesn't exist.
+func (d *FirestoreDocument) replacer(op operation, from interface{}, w writeOptions, mustExist bool) writer {
+	return writer{
+		direct: func(ctx context.Context) error {
+			return d.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+				s, err := tx.Get(op.ref)
+				if err != nil && status.Code(err) != codes.NotFound {
+					return err
+				}
+				if mustExist && !s.Exists() {
+					return ErrDocumentNotExist
+				}
+				if err := w.check(s); err != nil {
+					return err
+				}
+				return tx.Set(op.ref, from)
+			})
+		},
+		batch: func(wb *firestore.WriteBatch) error {
+			wb.Delete(op.ref, w.deletion(mustExist)...).Set(op.ref, from)
+			return nil
+		},
+		tx: func(tx *firestore.Transaction) error {
+			if err := tx.Delete(op.ref, w.deletion(mustExist)...); err != nil {
+				return err
+			}
+			return tx.Set(op.ref, from)
+		},
+	}
+}
+
+// Merge merges the provided data with the existing document (if any) in Firestore.
+//
+// Only the given fields (see Fields) are merged, all of them if none is given.
+// note: from has to be a map if no field is given.
+//
+// Preconditions (e.g. IfUpdatedAt) can be provided, a Conflict error is returned if they are not met.
+// They are not supported in a write batch.
+//  err := fuego.Document("users", "jsmith").Merge(ctx, user, document.Fields("FirstName", "LastName"))
+func (d *FirestoreDocument) Merge(ctx context.Context, from interface{}, opts ...WriteOption) error {
+	w := newWriteOptions(opts...)
+
+	op, err := d.writeOperation(ctx, "Document.Merge", from)
+	if err != nil {
+		return err
+	}
+	op.conditional = w.conditional()
+
+	opt := firestore.MergeAll
+	if len(w.fields) > 0 {
+		paths := make([]firestore.FieldPath, len(w.fields))
+		for i, field := range w.fields {
+			if paths[i], err = fieldPath(field); err != nil {
+				return d.opts.fail(ctx, op, err, op.attrs()...)
+			}
+		}
+		opt = firestore.Merge(paths...)
 	}
 
-	return true
+	return d.opts.write(ctx, d, op, writer{
+		direct: func(ctx context.Context) error {
+			if !w.conditional() {
+				_, err := op.ref.Set(ctx, from, opt)
+				return err
+			}
+
+			return d.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+				if err := w.checked(tx, op.ref); err != nil {
+					return err
+				}
+				return tx.Set(op.ref, from, opt)
+			})
+		},
+		batch: func(wb *firestore.WriteBatch) error {
+			if w.conditional() {
+				return ErrPreconditionNotSupported
+			}
+			wb.Set(op.ref, from, opt)
+			return nil
+		},
+		tx: func(tx *firestore.Transaction) error {
+			if w.conditional() {
+				if err := w.checked(tx, op.ref); err != nil {
+					return err
+				}
+			}
+			return tx.Set(op.ref, from, opt)
+		},
+	}, d.opts.logger().Value("", from))
 }
 
-// Delete removes a document from Firestore.
-func (d *FirestoreDocument) Delete(ctx context.Context) error {
-	ref := d.GetDocumentRef()
-	if d.InBatch() {
-		d.Batch().Delete(ref)
+// Update applies updates to the fields of an existing document in Firestore, in a single write.
+//
+// Paths are dot-separated (e.g. "Address.City"), Delete and ServerTimestamp can be used as values.
+// A NotFound error is returned if the document doesn't exist.
+//  err := fuego.Document("users", "jsmith").Update(ctx,
+//  	document.Update{Path: "Address.City", Value: "Dublin"},
+//  	document.Update{Path: "Tokens", Value: document.Delete},
+//  	document.Update{Path: "UpdatedAt", Value: document.ServerTimestamp},
+//  )
+func (d *FirestoreDocument) Update(ctx context.Context, updates ...Update) error {
+	if len(updates) == 0 {
 		return nil
 	}
-	_, err := ref.Delete(ctx)
+
+	op := operation{name: "Document.Update", ref: d.GetDocumentRef()}
+	return d.opts.update(ctx, d, op, updates)
+}
+
+// Retrieve a document from Firestore.
+//
+// to: the destination must be a pointer.
+func (d *FirestoreDocument) Retrieve(ctx context.Context, to interface{}) error {
+	op := operation{name: "Document.Retrieve", ref: d.GetDocumentRef()}
+	return d.opts.run(ctx, op, func(ctx context.Context) error {
+		s, err := d.opts.snapshot(ctx, op.ref)
+		if err != nil {
+			return err
+		}
+
+		if !s.Exists() {
+			return ErrDocumentNotExist
+		}
+
+		return s.DataTo(to)
+	})
+}
+
+// Meta holds the metadata of a document.
+type Meta struct {
+
+	// CreateTime is the time at which the document was created.
+	CreateTime time.Time
+
+	// UpdateTime is the time at which the document was last changed (see IfUpdatedAt).
+	UpdateTime time.Time
+
+	// ReadTime is the time at which the document was read.
+	ReadTime time.Time
+}
+
+// RetrieveWithMeta retrieves a document from Firestore, along with its metadata.
+//
+// to: the destination must be a pointer.
+//  user := User{}
+//  meta, err := fuego.Document("users", "jsmith").RetrieveWithMeta(ctx, &user)
+func (d *FirestoreDocument) RetrieveWithMeta(ctx context.Context, to interface{}) (Meta, error) {
+	op := operation{name: "Document.RetrieveWithMeta", ref: d.GetDocumentRef()}
+
+	var meta Meta
+	err := d.opts.run(ctx, op, func(ctx context.Context) error {
+		s, err := d.opts.snapshot(ctx, op.ref)
+		if err != nil {
+			return err
+		}
+
+		if !s.Exists() {
+			return ErrDocumentNotExist
+		}
+
+		if err := s.DataTo(to); err != nil {
+			return err
+		}
+
+		meta = Meta{CreateTime: s.CreateTime, UpdateTime: s.UpdateTime, ReadTime: s.ReadTime}
+		return nil
+	})
 	if err != nil {
-		return err
+		return Meta{}, err
 	}
 
-	return nil
+	return meta, nil
+}
+
+// Exists returns true if a given document exists, false otherwise.
+func (d *FirestoreDocument) Exists(ctx context.Context) bool {
+	op := operation{name: "Document.Exists", ref: d.GetDocumentRef()}
+	exists := false
+	err := d.opts.run(ctx, op, func(ctx context.Context) error {
+		s, err := d.opts.snapshot(ctx, op.ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		exists = s.Exists()
+		return nil
+	})
+
+	return err == nil && exists
+}
+
+// Delete removes a document from Firestore.
+//
+// Preconditions (e.g. IfUpdatedAt) can be provided, a Conflict error is returned if they are not met.
+func (d *FirestoreDocument) Delete(ctx context.Context, opts ...WriteOption) error {
+	w := newWriteOptions(opts...)
+	op := operation{name: "Document.Delete", ref: d.GetDocumentRef(), conditional: w.conditional()}
+	return d.opts.write(ctx, d, op, writer{
+		direct: func(ctx context.Context) error {
+			_, err := op.ref.Delete(ctx, w.deletion(false)...)
+			return err
+		},
+		batch: func(wb *firestore.WriteBatch) error {
+			wb.Delete(op.ref, w.deletion(false)...)
+			return nil
+		},
+		tx: func(tx *firestore.Transaction) error {
+			return tx.Delete(op.ref, w.deletion(false)...)
+		},
+	})
 }
 
 // Array returns a new Array.
@@ -152,14 +507,17 @@ func (d *FirestoreDocument) Array(name string) *Array {
 		Document:  d,
 		Name:      name,
 		firestore: d.firestore,
+		opts:      d.opts,
 	}
 }
 
 // String returns a new String.
 func (d *FirestoreDocument) String(name string) *String {
 	return &String{
-		Document: d,
-		Name:     name,
+		Document:  d,
+		Name:      name,
+		firestore: d.firestore,
+		opts:      d.opts,
 	}
 }
 
@@ -169,14 +527,27 @@ func (d *FirestoreDocument) Number(name string) *Number {
 		Document:  d,
 		Name:      name,
 		firestore: d.firestore,
+		opts:      d.opts,
+	}
+}
+
+// Float returns a new Float.
+func (d *FirestoreDocument) Float(name string) *Float {
+	return &Float{
+		Document:  d,
+		Name:      name,
+		firestore: d.firestore,
+		opts:      d.opts,
 	}
 }
 
 // Boolean returns a new Boolean.
 func (d *FirestoreDocument) Boolean(name string) *Boolean {
 	return &Boolean{
-		Document: d,
-		Name:     name,
+		Document:  d,
+		Name:      name,
+		firestore: d.firestore,
+		opts:      d.opts,
 	}
 }
 
@@ -186,14 +557,84 @@ func (d *FirestoreDocument) Map(name string) *Map {
 		Document:  d,
 		Name:      name,
 		firestore: d.firestore,
+		opts:      d.opts,
 	}
 }
 
 // Timestamp returns a new Timestamp.
 func (d *FirestoreDocument) Timestamp(name string) *Timestamp {
 	return &Timestamp{
-		Document: d,
-		Name:     name,
+		Document:  d,
+		Name:      name,
+		firestore: d.firestore,
+		opts:      d.opts,
+	}
+}
+
+// GeoPoint returns a new GeoPoint.
+func (d *FirestoreDocument) GeoPoint(name string) *GeoPoint {
+	return &GeoPoint{
+		Document:  d,
+		Name:      name,
+		firestore: d.firestore,
+		opts:      d.opts,
+	}
+}
+
+// Bytes returns a new Bytes.
+func (d *FirestoreDocument) Bytes(name string) *Bytes {
+	return &Bytes{
+		Document:  d,
+		Name:      name,
+		firestore: d.firestore,
+		opts:      d.opts,
+	}
+}
+
+// Reference returns a new Reference.
+func (d *FirestoreDocument) Reference(name string) *Reference {
+	return &Reference{
+		Document:  d,
+		Name:      name,
+		firestore: d.firestore,
+		opts:      d.opts,
+	}
+}
+
+// IsNull returns true if a specific field holds null, false if it holds another value or doesn't exist.
+//
+// A NotFound error is returned if the document doesn't exist.
+//  null, err := fuego.Document("users", "jsmith").IsNull(ctx, "MiddleName")
+func (d *FirestoreDocument) IsNull(ctx context.Context, name string) (bool, error) {
+	return isNull(ctx, "Document.IsNull", &TypedField[interface{}]{Document: d, Name: name, firestore: d.firestore, opts: d.opts})
+}
+
+// isNull returns true if a field holds null, false if it holds another value or doesn't exist.
+func isNull(ctx context.Context, name string, f *TypedField[interface{}]) (bool, error) {
+	value, exists, err := f.lookup(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	return exists && value == nil, nil
+}
+
+// SetNull sets a specific field to null, which unlike Delete keeps the field in the document.
+//  err := fuego.Document("users", "jsmith").SetNull(ctx, "MiddleName")
+func (d *FirestoreDocument) SetNull(ctx context.Context, name string, opts ...WriteOption) error {
+	return d.opts.set(ctx, "Document.SetNull", d, name, nil, opts...)
+}
+
+// State returns a new State, which transition history is stored in the field named after it followed by "History"
+// (for a nested field, the last key followed by "History").
+func (d *FirestoreDocument) State(name string, transitions Transitions) *State {
+	return &State{
+		Document:     d,
+		Name:         name,
+		Transitions:  transitions,
+		HistoryField: historyField(name),
+		firestore:    d.firestore,
+		opts:         d.opts,
 	}
 }
 
@@ -206,3 +647,13 @@ func (d *FirestoreDocument) InBatch() bool {
 func (d *FirestoreDocument) Batch() *firestore.WriteBatch {
 	return d.writeBatch
 }
+
+// InTransaction returns true if the document is part of a transaction, false otherwise.
+func (d *FirestoreDocument) InTransaction() bool {
+	return d.opts.transaction() != nil
+}
+
+// Transaction returns the pointer to the Transaction (if any), nil otherwise.
+func (d *FirestoreDocument) Transaction() *firestore.Transaction {
+	return d.opts.transaction()
+}
@@ -2,10 +2,33 @@ package document
 
 import "errors"
 
+// The errors returned by the document operations are *fuego.Error values wrapping the errors below (when relevant),
+// they can be matched with errors.Is.
 var (
 	// ErrDocumentNotExist indicates that the requested document doesn't exist.
 	ErrDocumentNotExist = errors.New("document: doesn't exist")
 
 	// ErrFieldRetrieve indicates that the requested field value could not be retrieved.
 	ErrFieldRetrieve = errors.New("field: couldn't retrieve the field value")
+
+	// ErrPreconditionFailed indicates that the document didn't meet the preconditions of a write (see IfUpdatedAt).
+	ErrPreconditionFailed = errors.New("document: precondition failed")
+
+	// ErrPreconditionNotSupported indicates that a write doesn't support preconditions in its mode (e.g. in a write batch).
+	ErrPreconditionNotSupported = errors.New("document: precondition not supported")
+
+	// ErrNoClient indicates that a transaction can't be run without a Firestore client (see Field).
+	ErrNoClient = errors.New("document: no Firestore client")
+
+	// ErrIndexOutOfRange indicates that an index is out of the range of an Array field.
+	ErrIndexOutOfRange = errors.New("document: index out of range")
+
+	// ErrInvalidLength indicates that the max. length of an Array field is invalid (see Array.Push).
+	ErrInvalidLength = errors.New("document: invalid length")
+
+	// ErrSizeLimitExceeded indicates that a value exceeds the max. size of a field (see Bytes.WithMaxSize).
+	ErrSizeLimitExceeded = errors.New("document: size limit exceeded")
+
+	// ErrIllegalTransition indicates that a transition isn't allowed from the current state of a State field.
+	ErrIllegalTransition = errors.New("document: illegal transition")
 )
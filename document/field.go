@@ -0,0 +1,291 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/remychantenay/fuego/document/internal"
+	"google.golang.org/genproto/googleapis/type/latlng"
+)
+
+// TypedField represents a document field holding values of type T.
+//
+// T can be any type that Firestore can encode and decode: basic types, time.Time,
+// structs (incl. `firestore` struct tags), slices ([]T) and maps (map[string]T).
+type TypedField[T any] struct {
+
+	// Document is the underlying document (incl. ID and ref).
+	Document Document
+
+	// Name is the name of the field.
+	Name string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// Field returns a specific field of a document, holding values of type T.
+//
+// The settings of the client (e.g. retries, logging) apply if doc is a *FirestoreDocument.
+// Otherwise, the operations using a transaction (e.g. CompareAndSwap) return an InvalidArgument error wrapping ErrNoClient,
+// unless doc is part of a transaction.
+//  field := document.Field[Address](fuego.Document("users", "jsmith"), "Address")
+func Field[T any](doc Document, name string) *TypedField[T] {
+	f := &TypedField[T]{
+		Document: doc,
+		Name:     name,
+	}
+
+	if d, ok := doc.(*FirestoreDocument); ok {
+		f.firestore = d.firestore
+		f.opts = d.opts
+	} else {
+		f.opts = newOptions(WithTransaction(doc.Transaction()))
+	}
+
+	return f
+}
+
+// Get returns the value of the field.
+//  address, err := document.Field[Address](fuego.Document("users", "jsmith"), "Address").Get(ctx)
+func (f *TypedField[T]) Get(ctx context.Context) (T, error) {
+	return f.get(ctx, "Field.Get")
+}
+
+// Set sets the value of the field.
+//
+// Preconditions (e.g. IfUpdatedAt) can be provided, a Conflict error is returned if they are not met.
+//  err := document.Field[Address](fuego.Document("users", "jsmith"), "Address").Set(ctx, address)
+func (f *TypedField[T]) Set(ctx context.Context, value T, opts ...WriteOption) error {
+	return f.set(ctx, "Field.Set", value, opts...)
+}
+
+// Delete removes the field from the document.
+//
+// Preconditions (e.g. IfUpdatedAt) can be provided, a Conflict error is returned if they are not met.
+//  err := document.Field[Address](fuego.Document("users", "jsmith"), "Address").Delete(ctx)
+func (f *TypedField[T]) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Field.Delete", f.Document, f.Name, firestore.Delete, opts...)
+}
+
+// CompareAndSwap sets the field to new only if it currently holds old, and returns true if the swap happened.
+// A missing field is considered as holding the zero value of T.
+//
+// The comparison and the update will be executed inside a transaction.
+//  swapped, err := document.Field[string](fuego.Document("orders", "123"), "Status").CompareAndSwap(ctx, "pending", "processing")
+func (f *TypedField[T]) CompareAndSwap(ctx context.Context, old, new T) (bool, error) {
+	return f.compareAndSwap(ctx, "Field.CompareAndSwap", old, new)
+}
+
+// compareAndSwap sets the field to new only if it currently holds old (see CompareAndSwap).
+func (f *TypedField[T]) compareAndSwap(ctx context.Context, name string, old, new T) (bool, error) {
+	swapped := false
+	err := f.modify(ctx, name, func(current T, exists bool) (T, bool, error) {
+		swapped = reflect.DeepEqual(current, old)
+		return new, swapped, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return swapped, nil
+}
+
+// Watch listens to the changes of the field and calls fn with its value (exists is false if the field or the document doesn't exist).
+// fn is called once with the current value, then every time the value changes.
+//
+// Watch blocks until ctx is done or fn returns an error, which is returned.
+//  err := document.Field[string](fuego.Document("orders", "123"), "Status").Watch(ctx, func(status string, exists bool) error {
+//  	fmt.Println("Status: ", status)
+//  	return nil
+//  })
+func (f *TypedField[T]) Watch(ctx context.Context, fn func(value T, exists bool) error) error {
+	op := operation{name: "Field.Watch", ref: f.Document.GetDocumentRef(), field: f.Name}
+	f.opts.logger().Operation(ctx, op.name, op.ref.Path, op.attrs()...)
+
+	it := op.ref.Snapshots(ctx)
+	defer it.Stop()
+
+	var previous T
+	previousExists, first := false, true
+	for {
+		s, err := it.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return f.opts.fail(ctx, op, err, op.attrs()...)
+		}
+
+		value, exists, err := f.decodeIfExists(s)
+		if err != nil {
+			return f.opts.fail(ctx, op, err, op.attrs()...)
+		}
+
+		if !first && exists == previousExists && reflect.DeepEqual(value, previous) {
+			continue // another field has changed
+		}
+		previous, previousExists, first = value, exists, false
+
+		if err := fn(value, exists); err != nil {
+			return err
+		}
+	}
+}
+
+// get returns the value of the field.
+func (f *TypedField[T]) get(ctx context.Context, name string) (T, error) {
+	op := operation{name: name, ref: f.Document.GetDocumentRef(), field: f.Name}
+
+	var value T
+	err := f.opts.run(ctx, op, func(ctx context.Context) error {
+		s, err := f.opts.snapshot(ctx, op.ref)
+		if err != nil {
+			return err
+		}
+
+		value, err = f.decode(s)
+		return err
+	})
+
+	return value, err
+}
+
+// lookup returns the value of the field, exists is false if the field doesn't exist.
+func (f *TypedField[T]) lookup(ctx context.Context, name string) (value T, exists bool, err error) {
+	op := operation{name: name, ref: f.Document.GetDocumentRef(), field: f.Name}
+
+	err = f.opts.run(ctx, op, func(ctx context.Context) error {
+		s, err := f.opts.snapshot(ctx, op.ref)
+		if err != nil {
+			return err
+		}
+
+		value, exists, err = f.decodeIfExists(s)
+		return err
+	})
+
+	return value, exists, err
+}
+
+// set sets the value of the field.
+func (f *TypedField[T]) set(ctx context.Context, name string, value T, opts ...WriteOption) error {
+	return f.opts.set(ctx, name, f.Document, f.Name, value, opts...)
+}
+
+// modify reads the field and writes the value returned by fn (if write is true), inside a transaction.
+//
+// fn is given the current value (the zero value of T if the field doesn't exist) and may be called more than once.
+// The preconditions of the write (if any) are checked against the document read.
+func (f *TypedField[T]) modify(ctx context.Context, name string, fn func(current T, exists bool) (next T, write bool, err error), opts ...WriteOption) error {
+	w := newWriteOptions(opts...)
+	op := operation{name: name, ref: f.Document.GetDocumentRef(), field: f.Name, conditional: w.conditional()}
+
+	return f.opts.runTransaction(ctx, f.firestore, op, func(ctx context.Context, tx *firestore.Transaction) error {
+		s, err := tx.Get(op.ref)
+		if err != nil {
+			return err
+		}
+
+		if err := w.check(s); err != nil {
+			return err
+		}
+
+		current, exists, err := f.decodeIfExists(s)
+		if err != nil {
+			return err
+		}
+
+		next, write, err := fn(current, exists)
+		if err != nil || !write {
+			return err
+		}
+
+		path, err := fieldPath(f.Name)
+		if err != nil {
+			return err
+		}
+
+		return tx.Update(op.ref, []Update{{FieldPath: path, Value: next}})
+	})
+}
+
+// decodeIfExists decodes the value of the field, exists is false if the field or the document doesn't exist.
+func (f *TypedField[T]) decodeIfExists(s *firestore.DocumentSnapshot) (value T, exists bool, err error) {
+	if !s.Exists() {
+		return value, false, nil
+	}
+
+	value, err = f.decode(s)
+
+	var fieldNotFound *firestore.FieldNotFoundError
+	if errors.As(err, &fieldNotFound) {
+		return value, false, nil
+	}
+
+	return value, err == nil, err
+}
+
+// decode decodes the value of the field from a document snapshot.
+//
+// The lenient conversions apply to the basic types, the other types are decoded by Firestore.
+func (f *TypedField[T]) decode(s *firestore.DocumentSnapshot) (T, error) {
+	var value T
+
+	path, err := fieldPath(f.Name)
+	if err != nil {
+		return value, err
+	}
+
+	v, err := s.DataAtPath(path)
+	if err != nil {
+		return value, err
+	}
+
+	if x, ok := v.(T); ok {
+		return x, nil
+	}
+
+	c := f.opts.conversion()
+
+	var converted interface{}
+	switch any(value).(type) {
+	case string:
+		converted, err = internal.ToString(v, c)
+	case int64:
+		converted, err = internal.ToInt64(v, c)
+	case float64:
+		converted, err = internal.ToFloat64(v, c)
+	case bool:
+		converted, err = internal.ToBool(v, c)
+	case time.Time:
+		converted, err = internal.ToTime(v, c)
+	case *latlng.LatLng:
+		converted, err = internal.ToGeoPoint(v, c)
+	case []byte:
+		converted, err = internal.ToBytes(v, c)
+	case *firestore.DocumentRef:
+		converted, err = internal.ToReference(v, c)
+	case map[string]interface{}:
+		converted, err = internal.ToMap(v, c)
+	case []interface{}:
+		converted, err = internal.ToSlice(v, c)
+	default:
+		if err := internal.DecodeAt(s, path, &value); err != nil {
+			return value, &internal.TypeError{Expected: fmt.Sprintf("%T", value), Actual: internal.TypeName(v)}
+		}
+		return value, nil
+	}
+
+	if err != nil {
+		return value, err
+	}
+
+	return converted.(T), nil
+}
@@ -0,0 +1,152 @@
+package document
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+)
+
+// FloatField provides the necessary to interact with a Firestore document field of type Number holding doubles.
+type FloatField interface {
+
+	// Retrieve returns the value of a specific field containing a number (float64).
+	Retrieve(ctx context.Context) (float64, error)
+
+	// Update the value of a specific field containing a number (float64).
+	Update(ctx context.Context, with float64, opts ...WriteOption) error
+
+	// Increment the value of a specific field containing a number (float64).
+	// If the field doesn't exist, it will be set to 1.
+	Increment(ctx context.Context) error
+
+	// Decrement the value of a specific field containing a number (float64).
+	// If the field doesn't exist, it will be set to 0.
+	Decrement(ctx context.Context) error
+
+	// Add adds delta (possibly negative) to the value of a specific field containing a number (float64).
+	// If the field doesn't exist, it will be set to delta.
+	Add(ctx context.Context, delta float64, opts ...WriteOption) error
+
+	// Multiply multiplies the value of a specific field containing a number (float64) by factor.
+	// If the field doesn't exist, it will be set to 0.
+	Multiply(ctx context.Context, factor float64, opts ...WriteOption) error
+
+	// Min sets a specific field containing a number (float64) to the minimum of its value and the given value.
+	// If the field doesn't exist, it will be set to the given value.
+	Min(ctx context.Context, with float64, opts ...WriteOption) error
+
+	// Max sets a specific field containing a number (float64) to the maximum of its value and the given value.
+	// If the field doesn't exist, it will be set to the given value.
+	Max(ctx context.Context, with float64, opts ...WriteOption) error
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
+}
+
+// Float represents a document field of type Number holding doubles (float64).
+//
+// Integers are retrieved as doubles as well.
+type Float struct {
+
+	// Document is the underlying document (incl. ID and ref).
+	Document Document
+
+	// Name is the name of the field.
+	Name string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *Float) field() *TypedField[float64] {
+	return &TypedField[float64]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
+}
+
+// Retrieve returns the content of a specific field for a given document.
+//  balance, err := fuego.Document("users", "jsmith").Float("Balance").Retrieve(ctx)
+func (f *Float) Retrieve(ctx context.Context) (float64, error) {
+	return f.field().get(ctx, "Float.Retrieve")
+}
+
+// Update the value of a specific field of type Float.
+//  err := fuego.Document("users", "jsmith").Float("Balance").Update(ctx, 42.5)
+func (f *Float) Update(ctx context.Context, with float64, opts ...WriteOption) error {
+	return f.field().set(ctx, "Float.Update", with, opts...)
+}
+
+// Increment the value of a specific field of type Float.
+//
+// The update will be executed inside a transaction, see Add for an update applied by Firestore.
+// If the field doesn't exist, it will be set to 1.
+//  err := fuego.Document("users", "jsmith").Float("Balance").Increment(ctx)
+func (f *Float) Increment(ctx context.Context) error {
+	return f.field().modify(ctx, "Float.Increment", func(current float64, exists bool) (float64, bool, error) {
+		return current + 1, true, nil
+	})
+}
+
+// Decrement the value of a specific field of type Float.
+//
+// The update will be executed inside a transaction, see Add for an update applied by Firestore.
+// If the field doesn't exist, it will be set to 0.
+//  err := fuego.Document("users", "jsmith").Float("Balance").Decrement(ctx)
+func (f *Float) Decrement(ctx context.Context) error {
+	return f.field().modify(ctx, "Float.Decrement", func(current float64, exists bool) (float64, bool, error) {
+		if !exists {
+			return 0, true, nil
+		}
+		return current - 1, true, nil
+	})
+}
+
+// Add adds delta (possibly negative) to the value of a specific field of type Float.
+//
+// The update is applied by Firestore: no transaction is needed (i.e. no contention between concurrent updates),
+// which also makes it usable in a write batch. It is not retried on failure, as it may have been applied.
+// If the field doesn't exist, it will be set to delta.
+//  err := fuego.Document("users", "jsmith").Float("Balance").Add(ctx, -12.5)
+func (f *Float) Add(ctx context.Context, delta float64, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Float.Add", f.Document, f.Name, firestore.Increment(delta), opts...)
+}
+
+// Multiply multiplies the value of a specific field of type Float by factor.
+//
+// The update will be executed inside a transaction.
+// If the field doesn't exist, it will be set to 0.
+//  err := fuego.Document("users", "jsmith").Float("Balance").Multiply(ctx, 1.05)
+func (f *Float) Multiply(ctx context.Context, factor float64, opts ...WriteOption) error {
+	return f.field().modify(ctx, "Float.Multiply", func(current float64, exists bool) (float64, bool, error) {
+		return current * factor, true, nil
+	}, opts...)
+}
+
+// Min sets a specific field of type Float to the minimum of its value and the given value (i.e. caps it).
+//
+// The update is applied by Firestore (see Add).
+// If the field doesn't exist, it will be set to the given value.
+//  err := fuego.Document("users", "jsmith").Float("Rating").Min(ctx, 5)
+func (f *Float) Min(ctx context.Context, with float64, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Float.Min", f.Document, f.Name, firestore.FieldTransformMinimum(with), opts...)
+}
+
+// Max sets a specific field of type Float to the maximum of its value and the given value (i.e. floors it).
+//
+// The update is applied by Firestore (see Add).
+// If the field doesn't exist, it will be set to the given value.
+//  err := fuego.Document("users", "jsmith").Float("Balance").Max(ctx, 0)
+func (f *Float) Max(ctx context.Context, with float64, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Float.Max", f.Document, f.Name, firestore.FieldTransformMaximum(with), opts...)
+}
+
+// Delete removes a specific field of type Float from the document.
+//  err := fuego.Document("users", "jsmith").Float("Balance").Delete(ctx)
+func (f *Float) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Float.Delete", f.Document, f.Name, Delete, opts...)
+}
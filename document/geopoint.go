@@ -0,0 +1,65 @@
+package document
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/genproto/googleapis/type/latlng"
+)
+
+// GeoPointField provides the necessary to interact with a Firestore document field of type GeoPoint.
+type GeoPointField interface {
+
+	// Retrieve returns the value of a specific field containing a geopoint (*latlng.LatLng).
+	Retrieve(ctx context.Context) (*latlng.LatLng, error)
+
+	// Update updates the value of a specific field containing a geopoint (*latlng.LatLng).
+	Update(ctx context.Context, with *latlng.LatLng, opts ...WriteOption) error
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
+}
+
+// GeoPoint represents a document field of type GeoPoint.
+//
+// To query documents by distance, locations have to be stored with their geohash (see the geo package).
+type GeoPoint struct {
+
+	// Document is the underlying document (incl. ID and ref).
+	Document Document
+
+	// Name is the name of the field.
+	Name string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *GeoPoint) field() *TypedField[*latlng.LatLng] {
+	return &TypedField[*latlng.LatLng]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
+}
+
+// Retrieve returns the content of a specific field for a given document.
+//  point, err := fuego.Document("shops", "123").GeoPoint("Entrance").Retrieve(ctx)
+func (f *GeoPoint) Retrieve(ctx context.Context) (*latlng.LatLng, error) {
+	return f.field().get(ctx, "GeoPoint.Retrieve")
+}
+
+// Update updates the value of a specific field of type GeoPoint.
+//  err := fuego.Document("shops", "123").GeoPoint("Entrance").Update(ctx, &latlng.LatLng{Latitude: 48.8584, Longitude: 2.2945})
+func (f *GeoPoint) Update(ctx context.Context, with *latlng.LatLng, opts ...WriteOption) error {
+	return f.field().set(ctx, "GeoPoint.Update", with, opts...)
+}
+
+// Delete removes a specific field of type GeoPoint from the document.
+//  err := fuego.Document("shops", "123").GeoPoint("Entrance").Delete(ctx)
+func (f *GeoPoint) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "GeoPoint.Delete", f.Document, f.Name, Delete, opts...)
+}
@@ -0,0 +1,102 @@
+package internal
+
+import "reflect"
+
+// InsertAt returns s with items inserted at index i (0 <= i <= len(s)), s being left untouched.
+func InsertAt[T any](s []T, i int, items ...T) []T {
+	result := make([]T, 0, len(s)+len(items))
+	result = append(result, s[:i]...)
+	result = append(result, items...)
+	return append(result, s[i:]...)
+}
+
+// RemoveAt returns s without the element at index i (0 <= i < len(s)), s being left untouched.
+func RemoveAt[T any](s []T, i int) []T {
+	result := make([]T, 0, len(s)-1)
+	result = append(result, s[:i]...)
+	return append(result, s[i+1:]...)
+}
+
+// RemoveWhere returns s without the elements matching fn and the number of elements removed, s being left untouched.
+func RemoveWhere[T any](s []T, fn func(T) bool) ([]T, int) {
+	result := make([]T, 0, len(s))
+	for _, v := range s {
+		if !fn(v) {
+			result = append(result, v)
+		}
+	}
+	return result, len(s) - len(result)
+}
+
+// Push returns s with items added at its end (or at its front if front is true), s being left untouched.
+//
+// If key is not nil, the elements are deduplicated by key: an element already in s is moved instead of being added twice.
+// The result is then truncated to its last (or first if front is true) maxLen elements.
+func Push[T any](s, items []T, maxLen int, front bool, key func(T) interface{}) []T {
+	result := make([]T, 0, len(s)+len(items))
+	if front {
+		result = append(append(result, items...), s...)
+	} else {
+		result = append(append(result, s...), items...)
+	}
+
+	if key != nil {
+		result = dedupe(result, !front, key)
+	}
+
+	if len(result) <= maxLen {
+		return result
+	}
+	if front {
+		return result[:maxLen]
+	}
+	return result[len(result)-maxLen:]
+}
+
+// dedupe returns s without the elements having the same key as another element,
+// the last occurrence being kept if last is true, the first one otherwise.
+func dedupe[T any](s []T, last bool, key func(T) interface{}) []T {
+	seen := keySet{hashable: make(map[interface{}]bool, len(s))}
+	keep := make([]bool, len(s))
+	for n := 0; n < len(s); n++ {
+		i := n
+		if last {
+			i = len(s) - 1 - n
+		}
+
+		keep[i] = seen.add(key(s[i]))
+	}
+
+	result := make([]T, 0, len(s))
+	for i, v := range s {
+		if keep[i] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// keySet is a set of keys, the keys which can't be map keys (e.g. maps, slices or nil) being compared with reflect.DeepEqual.
+type keySet struct {
+	hashable map[interface{}]bool
+	others   []interface{}
+}
+
+// add adds k to the set and returns true if it wasn't already part of it.
+func (s *keySet) add(k interface{}) bool {
+	if reflect.ValueOf(k).Comparable() {
+		if s.hashable[k] {
+			return false
+		}
+		s.hashable[k] = true
+		return true
+	}
+
+	for _, other := range s.others {
+		if reflect.DeepEqual(other, k) {
+			return false
+		}
+	}
+	s.others = append(s.others, k)
+	return true
+}
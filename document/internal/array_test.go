@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsertAt(t *testing.T) {
+
+	tests := []struct {
+		description string
+		with        []int
+		index       int
+		items       []int
+		want        []int
+	}{
+		{
+			description: "Empty slice",
+			with:        nil,
+			index:       0,
+			items:       []int{1},
+			want:        []int{1},
+		},
+		{
+			description: "Front",
+			with:        []int{2, 3},
+			index:       0,
+			items:       []int{0, 1},
+			want:        []int{0, 1, 2, 3},
+		},
+		{
+			description: "Middle",
+			with:        []int{0, 3},
+			index:       1,
+			items:       []int{1, 2},
+			want:        []int{0, 1, 2, 3},
+		},
+		{
+			description: "Back",
+			with:        []int{0, 1},
+			index:       2,
+			items:       []int{2},
+			want:        []int{0, 1, 2},
+		},
+	}
+
+	for _, test := range tests {
+		original := append([]int(nil), test.with...)
+		result := InsertAt(test.with, test.index, test.items...)
+
+		if !reflect.DeepEqual(result, test.want) {
+			t.Fatalf("%s -> Got %v but expected %v", test.description, result, test.want)
+		}
+
+		if !reflect.DeepEqual(test.with, original) {
+			t.Fatalf("%s -> The slice has been modified: %v", test.description, test.with)
+		}
+	}
+}
+
+func TestRemoveAt(t *testing.T) {
+	s := []int{0, 1, 2}
+
+	result := RemoveAt(s, 1)
+
+	if !reflect.DeepEqual(result, []int{0, 2}) {
+		t.Fatalf("Got %v but expected %v", result, []int{0, 2})
+	}
+
+	if !reflect.DeepEqual(s, []int{0, 1, 2}) {
+		t.Fatalf("The slice has been modified: %v", s)
+	}
+}
+
+func TestRemoveWhere(t *testing.T) {
+	result, removed := RemoveWhere([]int{0, 1, 2, 3, 4}, func(v int) bool {
+		return v%2 == 0
+	})
+
+	if !reflect.DeepEqual(result, []int{1, 3}) || removed != 3 {
+		t.Fatalf("Got %v (%d removed) but expected %v (%d removed)", result, removed, []int{1, 3}, 3)
+	}
+}
+
+func TestPush(t *testing.T) {
+	id := func(v int) interface{} { return v }
+
+	tests := []struct {
+		description string
+		with        []int
+		items       []int
+		maxLen      int
+		front       bool
+		key         func(int) interface{}
+		want        []int
+	}{
+		{
+			description: "Empty slice",
+			items:       []int{1, 2},
+			maxLen:      3,
+			want:        []int{1, 2},
+		},
+		{
+			description: "Trimmed from the front",
+			with:        []int{1, 2, 3},
+			items:       []int{4, 5},
+			maxLen:      3,
+			want:        []int{3, 4, 5},
+		},
+		{
+			description: "Trimmed from the back",
+			with:        []int{3, 2, 1},
+			items:       []int{5, 4},
+			maxLen:      3,
+			front:       true,
+			want:        []int{5, 4, 3},
+		},
+		{
+			description: "Duplicates without key",
+			with:        []int{1, 2},
+			items:       []int{1},
+			maxLen:      5,
+			want:        []int{1, 2, 1},
+		},
+		{
+			description: "Re-pushed item moved to the end",
+			with:        []int{1, 2, 3},
+			items:       []int{1},
+			maxLen:      3,
+			key:         id,
+			want:        []int{2, 3, 1},
+		},
+		{
+			description: "Re-pushed item moved to the front",
+			with:        []int{3, 2, 1},
+			items:       []int{1},
+			maxLen:      3,
+			front:       true,
+			key:         id,
+			want:        []int{1, 3, 2},
+		},
+		{
+			description: "Duplicates among the items",
+			with:        []int{1},
+			items:       []int{2, 1, 2},
+			maxLen:      3,
+			key:         id,
+			want:        []int{1, 2},
+		},
+	}
+
+	for _, test := range tests {
+		original := append([]int(nil), test.with...)
+		result := Push(test.with, test.items, test.maxLen, test.front, test.key)
+
+		if !reflect.DeepEqual(result, test.want) {
+			t.Fatalf("%s -> Got %v but expected %v", test.description, result, test.want)
+		}
+
+		if !reflect.DeepEqual(test.with, original) {
+			t.Fatalf("%s -> The slice has been modified: %v", test.description, test.with)
+		}
+	}
+}
+
+func TestPush_UnhashableKeys(t *testing.T) {
+	type item struct {
+		ID   string
+		Tags interface{}
+	}
+
+	a := map[string]interface{}{"ID": "a"}
+	b := map[string]interface{}{"ID": "b"}
+
+	tests := []struct {
+		description string
+		key         func(interface{}) interface{}
+		want        []interface{}
+	}{
+		{
+			description: "Map keys",
+			key:         func(v interface{}) interface{} { return v },
+			want:        []interface{}{b, a},
+		},
+		{
+			description: "Slice keys",
+			key:         func(v interface{}) interface{} { return []interface{}{v.(map[string]interface{})["ID"]} },
+			want:        []interface{}{b, a},
+		},
+		{
+			description: "Comparable type holding a map",
+			key: func(v interface{}) interface{} {
+				m := v.(map[string]interface{})
+				return item{ID: m["ID"].(string), Tags: m}
+			},
+			want: []interface{}{b, a},
+		},
+		{
+			description: "Nil keys",
+			key:         func(v interface{}) interface{} { return nil },
+			want:        []interface{}{a},
+		},
+	}
+
+	for _, test := range tests {
+		result := Push([]interface{}{a, b}, []interface{}{map[string]interface{}{"ID": "a"}}, 3, false, test.key)
+
+		if !reflect.DeepEqual(result, test.want) {
+			t.Fatalf("%s -> Got %v but expected %v", test.description, result, test.want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/genproto/googleapis/type/latlng"
+)
+
+// Conversion is a set of lenient conversions applied when decoding field values.
+type Conversion uint8
+
+const (
+	// ConvertIntegralFloats converts doubles without fractional part (e.g. 42.0) to integers.
+	ConvertIntegralFloats Conversion = 1 << iota
+
+	// ConvertNumericStrings converts strings holding a number (e.g. "42") to numbers.
+	ConvertNumericStrings
+
+	// ConvertNullToZero converts null values to the zero value of the expected type.
+	ConvertNullToZero
+)
+
+// Has returns true if all the conversions of o are enabled in c.
+func (c Conversion) Has(o Conversion) bool {
+	return c&o == o
+}
+
+// TypeError indicates that a field value doesn't have the expected Firestore type.
+type TypeError struct {
+
+	// Expected is the expected Firestore type (e.g. "integer").
+	Expected string
+
+	// Actual is the actual Firestore type (e.g. "double").
+	Actual string
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("expected %s but got %s", e.Expected, e.Actual)
+}
+
+// TypeName returns the name of the Firestore type of a decoded value.
+func TypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case int64:
+		return "integer"
+	case float64:
+		return "double"
+	case string:
+		return "string"
+	case []byte:
+		return "bytes"
+	case time.Time:
+		return "timestamp"
+	case *latlng.LatLng:
+		return "geopoint"
+	case *firestore.DocumentRef:
+		return "reference"
+	case map[string]interface{}:
+		return "map"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func mismatch(expected string, v interface{}) error {
+	return &TypeError{Expected: expected, Actual: TypeName(v)}
+}
+
+// ToString decodes a string.
+func ToString(v interface{}, c Conversion) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return "", nil
+		}
+	}
+	return "", mismatch("string", v)
+}
+
+// ToInt64 decodes an integer.
+func ToInt64(v interface{}, c Conversion) (int64, error) {
+	switch x := v.(type) {
+	case int64:
+		return x, nil
+	case float64:
+		if c.Has(ConvertIntegralFloats) && x == math.Trunc(x) && x >= math.MinInt64 && x < math.MaxInt64 {
+			return int64(x), nil
+		}
+	case string:
+		if c.Has(ConvertNumericStrings) {
+			if i, err := strconv.ParseInt(strings.TrimSpace(x), 10, 64); err == nil {
+				return i, nil
+			}
+		}
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return 0, nil
+		}
+	}
+	return 0, mismatch("integer", v)
+}
+
+// ToFloat64 decodes a double, integers being converted as they are numbers as well.
+func ToFloat64(v interface{}, c Conversion) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case int64:
+		return float64(x), nil
+	case string:
+		if c.Has(ConvertNumericStrings) {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil {
+				return f, nil
+			}
+		}
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return 0, nil
+		}
+	}
+	return 0, mismatch("double", v)
+}
+
+// ToBool decodes a boolean.
+func ToBool(v interface{}, c Conversion) (bool, error) {
+	switch x := v.(type) {
+	case bool:
+		return x, nil
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return false, nil
+		}
+	}
+	return false, mismatch("boolean", v)
+}
+
+// ToTime decodes a timestamp.
+func ToTime(v interface{}, c Conversion) (time.Time, error) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, nil
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return time.Time{}, nil
+		}
+	}
+	return time.Time{}, mismatch("timestamp", v)
+}
+
+// ToGeoPoint decodes a geopoint.
+func ToGeoPoint(v interface{}, c Conversion) (*latlng.LatLng, error) {
+	switch x := v.(type) {
+	case *latlng.LatLng:
+		return x, nil
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return nil, nil
+		}
+	}
+	return nil, mismatch("geopoint", v)
+}
+
+// ToBytes decodes bytes.
+func ToBytes(v interface{}, c Conversion) ([]byte, error) {
+	switch x := v.(type) {
+	case []byte:
+		return x, nil
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return nil, nil
+		}
+	}
+	return nil, mismatch("bytes", v)
+}
+
+// ToReference decodes a reference.
+func ToReference(v interface{}, c Conversion) (*firestore.DocumentRef, error) {
+	switch x := v.(type) {
+	case *firestore.DocumentRef:
+		return x, nil
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return nil, nil
+		}
+	}
+	return nil, mismatch("reference", v)
+}
+
+// ToMap decodes a map.
+func ToMap(v interface{}, c Conversion) (map[string]interface{}, error) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		return x, nil
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return map[string]interface{}{}, nil
+		}
+	}
+	return nil, mismatch("map", v)
+}
+
+// ToSlice decodes an array.
+func ToSlice(v interface{}, c Conversion) ([]interface{}, error) {
+	switch x := v.(type) {
+	case []interface{}:
+		return x, nil
+	case nil:
+		if c.Has(ConvertNullToZero) {
+			return []interface{}{}, nil
+		}
+	}
+	return nil, mismatch("array", v)
+}
+
+// DecodeAt decodes the value at a given path of a document into dst (a non-nil pointer),
+// following the same rules as firestore.DocumentSnapshot.DataTo (e.g. struct tags).
+//
+// The path is matched with struct tags, a *PathError is returned if one of its keys can't be expressed
+// as a tag name (i.e. contains a comma or is "-").
+func DecodeAt(s *firestore.DocumentSnapshot, path []string, dst interface{}) error {
+	for _, key := range path {
+		if key == "-" || strings.Contains(key, ",") {
+			return &PathError{Path: JoinPath(path...), Reason: fmt.Sprintf("key %q not supported by the decoding of custom types", key)}
+		}
+	}
+
+	dv := reflect.ValueOf(dst).Elem()
+
+	// Wrapping the destination type in (nested) structs only holding the requested path,
+	// the other fields of the document are ignored when decoding.
+	typ := dv.Type()
+	for i := len(path) - 1; i >= 0; i-- {
+		typ = reflect.StructOf([]reflect.StructField{{
+			Name: "V",
+			Type: typ,
+			Tag:  reflect.StructTag("firestore:" + strconv.Quote(path[i])),
+		}})
+	}
+
+	holder := reflect.New(typ)
+	if err := s.DataTo(holder.Interface()); err != nil {
+		return err
+	}
+
+	v := holder.Elem()
+	for range path {
+		v = v.Field(0)
+	}
+	dv.Set(v)
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/type/latlng"
+)
+
+func TestToInt64(t *testing.T) {
+
+	tests := []struct {
+		description string
+		with        interface{}
+		conversion  Conversion
+		want        int64
+		wantErr     bool
+	}{
+		{
+			description: "Integer",
+			with:        int64(42),
+			want:        42,
+		},
+		{
+			description: "Integral double without conversion",
+			with:        float64(42),
+			wantErr:     true,
+		},
+		{
+			description: "Integral double",
+			with:        float64(42),
+			conversion:  ConvertIntegralFloats,
+			want:        42,
+		},
+		{
+			description: "Non integral double",
+			with:        42.5,
+			conversion:  ConvertIntegralFloats,
+			wantErr:     true,
+		},
+		{
+			description: "Numeric string",
+			with:        " 42",
+			conversion:  ConvertNumericStrings,
+			want:        42,
+		},
+		{
+			description: "Non numeric string",
+			with:        "forty-two",
+			conversion:  ConvertNumericStrings,
+			wantErr:     true,
+		},
+		{
+			description: "Null without conversion",
+			with:        nil,
+			wantErr:     true,
+		},
+		{
+			description: "Null",
+			with:        nil,
+			conversion:  ConvertNullToZero,
+			want:        0,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := ToInt64(test.with, test.conversion)
+
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%s -> Got error %v, expected an error: %t", test.description, err, test.wantErr)
+		}
+
+		if result != test.want {
+			t.Fatalf("%s -> Got %d but expected %d", test.description, result, test.want)
+		}
+	}
+}
+
+func TestToFloat64(t *testing.T) {
+
+	tests := []struct {
+		description string
+		with        interface{}
+		conversion  Conversion
+		want        float64
+		wantErr     bool
+	}{
+		{
+			description: "Double",
+			with:        42.5,
+			want:        42.5,
+		},
+		{
+			description: "Integer",
+			with:        int64(42),
+			want:        42,
+		},
+		{
+			description: "Numeric string without conversion",
+			with:        "42.5",
+			wantErr:     true,
+		},
+		{
+			description: "Numeric string",
+			with:        " 42.5",
+			conversion:  ConvertNumericStrings,
+			want:        42.5,
+		},
+		{
+			description: "Boolean",
+			with:        true,
+			conversion:  ConvertIntegralFloats | ConvertNumericStrings | ConvertNullToZero,
+			wantErr:     true,
+		},
+		{
+			description: "Null",
+			with:        nil,
+			conversion:  ConvertNullToZero,
+			want:        0,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := ToFloat64(test.with, test.conversion)
+
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%s -> Got error %v, expected an error: %t", test.description, err, test.wantErr)
+		}
+
+		if result != test.want {
+			t.Fatalf("%s -> Got %f but expected %f", test.description, result, test.want)
+		}
+	}
+}
+
+func TestTypeError(t *testing.T) {
+	_, err := ToString(float64(42), ConvertIntegralFloats|ConvertNumericStrings)
+
+	var typeErr *TypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Expected a *TypeError, got %v", err)
+	}
+
+	if typeErr.Expected != "string" || typeErr.Actual != "double" {
+		t.Fatalf("Got %q but expected %q", err.Error(), "expected string but got double")
+	}
+}
+
+func TestTypeName(t *testing.T) {
+
+	tests := []struct {
+		with interface{}
+		want string
+	}{
+		{with: nil, want: "null"},
+		{with: true, want: "boolean"},
+		{with: int64(1), want: "integer"},
+		{with: 1.5, want: "double"},
+		{with: "a", want: "string"},
+		{with: []byte("a"), want: "bytes"},
+		{with: time.Now(), want: "timestamp"},
+		{with: map[string]interface{}{}, want: "map"},
+		{with: []interface{}{}, want: "array"},
+	}
+
+	for _, test := range tests {
+		result := TypeName(test.with)
+
+		if result != test.want {
+			t.Fatalf("Got %s but expected %s", result, test.want)
+		}
+	}
+}
+
+func TestNullToZero(t *testing.T) {
+	if _, err := ToBool(nil, 0); err == nil {
+		t.Fatalf("Expected an error")
+	}
+
+	if v, err := ToBool(nil, ConvertNullToZero); err != nil || v {
+		t.Fatalf("Got %t, %v but expected false, nil", v, err)
+	}
+
+	if v, err := ToMap(nil, ConvertNullToZero); err != nil || v == nil {
+		t.Fatalf("Got %v, %v but expected an empty map", v, err)
+	}
+
+	if v, err := ToTime(nil, ConvertNullToZero); err != nil || !v.IsZero() {
+		t.Fatalf("Got %v, %v but expected a zero time", v, err)
+	}
+
+	if v, err := ToGeoPoint(nil, ConvertNullToZero); err != nil || v != nil {
+		t.Fatalf("Got %v, %v but expected nil", v, err)
+	}
+
+	if v, err := ToBytes(nil, ConvertNullToZero); err != nil || v != nil {
+		t.Fatalf("Got %v, %v but expected nil", v, err)
+	}
+
+	if v, err := ToReference(nil, ConvertNullToZero); err != nil || v != nil {
+		t.Fatalf("Got %v, %v but expected nil", v, err)
+	}
+}
+
+func TestToBytes(t *testing.T) {
+	if v, err := ToBytes([]byte("fuego"), 0); err != nil || string(v) != "fuego" {
+		t.Fatalf("Got %v, %v but expected %v", v, err, []byte("fuego"))
+	}
+
+	var typeErr *TypeError
+	if _, err := ToBytes("fuego", ConvertIntegralFloats|ConvertNumericStrings|ConvertNullToZero); !errors.As(err, &typeErr) || typeErr.Expected != "bytes" || typeErr.Actual != "string" {
+		t.Fatalf("Expected a TypeError, got %v", err)
+	}
+}
+
+func TestToReference(t *testing.T) {
+	var typeErr *TypeError
+	if _, err := ToReference("users/jsmith", ConvertIntegralFloats|ConvertNumericStrings|ConvertNullToZero); !errors.As(err, &typeErr) || typeErr.Expected != "reference" {
+		t.Fatalf("Expected a TypeError, got %v", err)
+	}
+}
+
+func TestToGeoPoint(t *testing.T) {
+	point := &latlng.LatLng{Latitude: 48.8584, Longitude: 2.2945}
+	if v, err := ToGeoPoint(point, 0); err != nil || v != point {
+		t.Fatalf("Got %v, %v but expected %v", v, err, point)
+	}
+
+	var typeErr *TypeError
+	if _, err := ToGeoPoint("48.8584,2.2945", ConvertIntegralFloats|ConvertNumericStrings|ConvertNullToZero); !errors.As(err, &typeErr) || typeErr.Expected != "geopoint" {
+		t.Fatalf("Expected a TypeError, got %v", err)
+	}
+}
+
+func TestDecodeAt_UnsupportedKeys(t *testing.T) {
+	for _, path := range [][]string{{"M", "a,b"}, {"-"}, {"M", "-", "a"}} {
+		var value struct{ A string }
+
+		err := DecodeAt(nil, path, &value)
+
+		var pathErr *PathError
+		if !errors.As(err, &pathErr) {
+			t.Fatalf("%v -> A *PathError is expected, got %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Leaf is a value to set at a given path.
+type Leaf struct {
+
+	// Path is the path of the value.
+	Path []string
+
+	// Value is the value to set.
+	Value interface{}
+}
+
+// Flatten returns the leaves of data (i.e. its values which are not maps, nested maps being walked key by key),
+// their paths being prefixed with prefix. The leaves are sorted by path.
+//
+// Nested maps without keys have no leaves.
+func Flatten(prefix []string, data map[string]interface{}) []Leaf {
+	var leaves []Leaf
+	flatten(prefix, reflect.ValueOf(data), &leaves)
+	return leaves
+}
+
+func flatten(prefix []string, m reflect.Value, leaves *[]Leaf) {
+	keys := make([]string, 0, m.Len())
+	for _, k := range m.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := append(prefix[:len(prefix):len(prefix)], key)
+		v := m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key()))
+		for v.Kind() == reflect.Interface && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+			flatten(path, v, leaves)
+			continue
+		}
+
+		*leaves = append(*leaves, Leaf{Path: path, Value: v.Interface()})
+	}
+}
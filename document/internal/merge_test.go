@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+
+	data := map[string]interface{}{
+		"Theme": "dark",
+		"Notifications": map[string]interface{}{
+			"Email": true,
+			"Push": map[string]bool{
+				"Mentions": false,
+			},
+		},
+		"Empty": map[string]interface{}{},
+		"Tags":  []interface{}{"a", "b"},
+		"Null":  nil,
+	}
+
+	want := []Leaf{
+		{Path: []string{"Settings", "Notifications", "Email"}, Value: true},
+		{Path: []string{"Settings", "Notifications", "Push", "Mentions"}, Value: false},
+		{Path: []string{"Settings", "Null"}, Value: nil},
+		{Path: []string{"Settings", "Tags"}, Value: []interface{}{"a", "b"}},
+		{Path: []string{"Settings", "Theme"}, Value: "dark"},
+	}
+
+	result := Flatten([]string{"Settings"}, data)
+
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("Got %v but expected %v", result, want)
+	}
+}
@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PathError indicates that a field path is invalid.
+type PathError struct {
+
+	// Path is the invalid field path.
+	Path string
+
+	// Reason describes why the field path is invalid.
+	Reason string
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("invalid field path %q: %s", e.Path, e.Reason)
+}
+
+// ParsePath parses a dot-separated field path (e.g. "Address.City").
+//
+// Keys containing dots (or any other character) can be quoted with backticks (e.g. "Tokens.`web.app`"),
+// backticks and backslashes being escaped with a backslash inside quoted keys.
+func ParsePath(path string) ([]string, error) {
+	if len(path) == 0 {
+		return nil, &PathError{Path: path, Reason: "empty path"}
+	}
+
+	var keys []string
+	for i := 0; i <= len(path); {
+		if i == len(path) || path[i] == '.' {
+			return nil, &PathError{Path: path, Reason: "empty key"}
+		}
+
+		var key string
+		if path[i] == '`' {
+			var b strings.Builder
+			closed := false
+			for i++; i < len(path); i++ {
+				c := path[i]
+				if c == '\\' && i+1 < len(path) {
+					i++
+					b.WriteByte(path[i])
+					continue
+				}
+				if c == '`' {
+					closed = true
+					i++
+					break
+				}
+				b.WriteByte(c)
+			}
+
+			if !closed {
+				return nil, &PathError{Path: path, Reason: "unterminated backtick"}
+			}
+			key = b.String()
+			if len(key) == 0 {
+				return nil, &PathError{Path: path, Reason: "empty key"}
+			}
+		} else {
+			end := strings.IndexAny(path[i:], ".`")
+			if end < 0 {
+				end = len(path) - i
+			}
+			if i+end < len(path) && path[i+end] == '`' {
+				return nil, &PathError{Path: path, Reason: "unexpected backtick"}
+			}
+			key = path[i : i+end]
+			i += end
+		}
+
+		keys = append(keys, key)
+		if i == len(path) {
+			return keys, nil
+		}
+		if path[i] != '.' {
+			return nil, &PathError{Path: path, Reason: "expected a dot after a quoted key"}
+		}
+		i++
+	}
+
+	return keys, nil
+}
+
+// QuoteKey returns a key as it appears in a dot-separated field path: as is if it is a simple identifier
+// (i.e. letters, digits and underscores, not starting with a digit), quoted with backticks otherwise.
+func QuoteKey(key string) string {
+	if isIdentifier(key) {
+		return key
+	}
+
+	var b strings.Builder
+	b.WriteByte('`')
+	for i := 0; i < len(key); i++ {
+		if key[i] == '`' || key[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(key[i])
+	}
+	b.WriteByte('`')
+	return b.String()
+}
+
+// JoinPath returns the dot-separated representation of a field path, quoting the keys when needed.
+func JoinPath(keys ...string) string {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = QuoteKey(key)
+	}
+	return strings.Join(quoted, ".")
+}
+
+func isIdentifier(key string) bool {
+	if len(key) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+
+	tests := []struct {
+		description string
+		with        string
+		want        []string
+		wantErr     bool
+	}{
+		{
+			description: "Single key",
+			with:        "Tokens",
+			want:        []string{"Tokens"},
+		},
+		{
+			description: "Nested keys",
+			with:        "Address.City",
+			want:        []string{"Address", "City"},
+		},
+		{
+			description: "Non identifier key",
+			with:        "Settings.dark-mode",
+			want:        []string{"Settings", "dark-mode"},
+		},
+		{
+			description: "Quoted key with a dot",
+			with:        "Tokens.`web.app`",
+			want:        []string{"Tokens", "web.app"},
+		},
+		{
+			description: "Quoted key with escaped characters",
+			with:        "`a\\`b\\\\c`.d",
+			want:        []string{"a`b\\c", "d"},
+		},
+		{
+			description: "Empty path",
+			with:        "",
+			wantErr:     true,
+		},
+		{
+			description: "Empty key",
+			with:        "Address..City",
+			wantErr:     true,
+		},
+		{
+			description: "Trailing dot",
+			with:        "Address.",
+			wantErr:     true,
+		},
+		{
+			description: "Unterminated backtick",
+			with:        "Tokens.`web.app",
+			wantErr:     true,
+		},
+		{
+			description: "Backtick inside an unquoted key",
+			with:        "Tok`ens",
+			wantErr:     true,
+		},
+		{
+			description: "Characters after a quoted key",
+			with:        "`web`app",
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := ParsePath(test.with)
+
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%s -> Expected an error but got %v", test.description, result)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s -> Unexpected error: %v", test.description, err)
+		}
+
+		if !reflect.DeepEqual(result, test.want) {
+			t.Fatalf("%s -> Got %q but expected %q", test.description, result, test.want)
+		}
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+
+	tests := []struct {
+		description string
+		with        []string
+		want        string
+	}{
+		{
+			description: "Identifiers",
+			with:        []string{"Address", "City_2"},
+			want:        "Address.City_2",
+		},
+		{
+			description: "Key with a dot",
+			with:        []string{"Tokens", "web.app"},
+			want:        "Tokens.`web.app`",
+		},
+		{
+			description: "Key starting with a digit",
+			with:        []string{"Scores", "2024"},
+			want:        "Scores.`2024`",
+		},
+		{
+			description: "Key with a backtick and a backslash",
+			with:        []string{"a`b\\c"},
+			want:        "`a\\`b\\\\c`",
+		},
+	}
+
+	for _, test := range tests {
+		result := JoinPath(test.with...)
+
+		if result != test.want {
+			t.Fatalf("%s -> Got %q but expected %q", test.description, result, test.want)
+		}
+
+		// Round trip
+		keys, err := ParsePath(result)
+		if err != nil {
+			t.Fatalf("%s -> Unexpected error: %v", test.description, err)
+		}
+
+		if !reflect.DeepEqual(keys, test.with) {
+			t.Fatalf("%s -> Got %q but expected %q", test.description, keys, test.with)
+		}
+	}
+}
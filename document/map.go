@@ -2,6 +2,7 @@ package document
 
 import (
 	"context"
+	"sort"
 
 	"cloud.google.com/go/firestore"
 	"github.com/remychantenay/fuego/document/internal"
@@ -15,12 +16,17 @@ type MapField interface {
 	// note : the returned values will require type assertion.
 	Retrieve(ctx context.Context) (map[string]interface{}, error)
 
-	// Merge will merge the provided data with the existing data (if any) of a Map field.
-	// Note: this is the default behaviour with Firestore.
-	Merge(ctx context.Context, data map[string]interface{}) error
+	// Merge will merge the provided data with the existing data (if any) of a Map field, nested maps included.
+	Merge(ctx context.Context, data map[string]interface{}, opts ...MergeOption) error
 
-	// Override will override the existing data (if any)  withthe provided data.
-	Override(ctx context.Context, data map[string]interface{}) error
+	// Override will replace the existing data (if any) with the provided data.
+	Override(ctx context.Context, data map[string]interface{}, opts ...WriteOption) error
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
+
+	// DeleteKeys removes the given keys from a Map field.
+	DeleteKeys(ctx context.Context, keys []string, opts ...WriteOption) error
 }
 
 // Map represents a document field of type Map.
@@ -33,48 +39,253 @@ type Map struct {
 	Name string
 
 	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *Map) field() *TypedField[map[string]interface{}] {
+	return &TypedField[map[string]interface{}]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
 }
 
 // Retrieve returns the content of a specific field for a given document.
 func (f *Map) Retrieve(ctx context.Context) (map[string]interface{}, error) {
-	value, err := internal.RetrieveFieldValue(ctx, f.Document.GetDocumentRef(), f.Name)
+	return f.field().get(ctx, "Map.Retrieve")
+}
+
+// MergeOption configures Map.Merge. The write options (e.g. IfUpdatedAt) are merge options as well.
+type MergeOption interface {
+	applyMerge(*mergeOptions)
+}
+
+// mergeOption is an option specific to Map.Merge.
+type mergeOption func(*mergeOptions)
+
+// applyMerge applies the option to the settings of Map.Merge.
+func (o mergeOption) applyMerge(m *mergeOptions) {
+	o(m)
+}
+
+// applyMerge adds the write option to the settings of Map.Merge.
+func (o WriteOption) applyMerge(m *mergeOptions) {
+	m.write = append(m.write, o)
+}
+
+// Shallow restricts Map.Merge to the top-level keys of the given data: nested maps replace the existing ones
+// instead of being merged key by key.
+//  err := fuego.Document("users", "jsmith").Map("Settings").Merge(ctx, settings, document.Shallow)
+var Shallow MergeOption = mergeOption(func(o *mergeOptions) {
+	o.shallow = true
+})
+
+// mergeOptions holds the settings of Map.Merge.
+type mergeOptions struct {
+	shallow bool
+	write   []WriteOption
+}
+
+// newMergeOptions creates and returns mergeOptions with the provided MergeOption(s) applied.
+func newMergeOptions(opts ...MergeOption) mergeOptions {
+	o := mergeOptions{}
+	for _, opt := range opts {
+		opt.applyMerge(&o)
+	}
+	return o
+}
+
+// Merge merges the value of a specific Map field: the given keys are set, the others are left untouched.
+//
+// Nested maps are merged key by key as well, unless the Shallow option is given in which case they replace
+// the existing ones. Nested maps without keys are ignored. The document has to exist, the field is created if needed.
+//  err := fuego.Document("users", "jsmith").Map("Tokens").Merge(ctx, map[string]interface{}{"Android": "AND_123"})
+func (f *Map) Merge(ctx context.Context, data map[string]interface{}, opts ...MergeOption) error {
+	op := operation{name: "Map.Merge", ref: f.Document.GetDocumentRef(), field: f.Name}
+	path, err := fieldPath(f.Name)
 	if err != nil {
-		return nil, err
+		return f.opts.fail(ctx, op, err, op.attrs()...)
 	}
 
-	return value.(map[string]interface{}), nil
-}
+	o := newMergeOptions(opts...)
 
-// Merge merges the value of a specific Map field.
-func (f *Map) Merge(ctx context.Context, data map[string]interface{}) error {
+	var updates []Update
+	if o.shallow {
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
 
-	ref := f.Document.GetDocumentRef()
-	m := map[string]interface{}{
-		f.Name: data,
+		for _, key := range keys {
+			updates = append(updates, Update{FieldPath: append(path[:len(path):len(path)], key), Value: data[key]})
+		}
+	} else {
+		for _, leaf := range internal.Flatten(path, data) {
+			updates = append(updates, Update{FieldPath: leaf.Path, Value: leaf.Value})
+		}
 	}
 
-	if f.Document.InBatch() {
-		f.Document.Batch().Set(ref, m, firestore.MergeAll)
+	if len(updates) == 0 {
 		return nil
 	}
 
-	_, err := ref.Set(ctx, m, firestore.MergeAll)
-	return err
+	return f.opts.update(ctx, f.Document, op, updates, o.write...)
 }
 
-// Override simply update (override) the field with a given Map.
-func (f *Map) Override(ctx context.Context, data map[string]interface{}) error {
+// Override replaces the value of a specific Map field: the keys which are not in data are removed.
+//
+// The other fields of the document are left untouched.
+//  err := fuego.Document("users", "jsmith").Map("Tokens").Override(ctx, map[string]interface{}{"Android": "AND_123"})
+func (f *Map) Override(ctx context.Context, data map[string]interface{}, opts ...WriteOption) error {
+	return f.field().set(ctx, "Map.Override", data, opts...)
+}
 
-	ref := f.Document.GetDocumentRef()
-	m := map[string]interface{}{
-		f.Name: data,
-	}
+// Delete removes a specific field of type Map from the document.
+//  err := fuego.Document("users", "jsmith").Map("Tokens").Delete(ctx)
+func (f *Map) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Map.Delete", f.Document, f.Name, Delete, opts...)
+}
 
-	if f.Document.InBatch() {
-		f.Document.Batch().Set(ref, m, firestore.MergeAll)
+// DeleteKeys removes the given keys from a specific field of type Map, in a single write.
+//  err := fuego.Document("users", "jsmith").Map("Tokens").DeleteKeys(ctx, []string{"Android", "IOS"})
+func (f *Map) DeleteKeys(ctx context.Context, keys []string, opts ...WriteOption) error {
+	if len(keys) == 0 {
 		return nil
 	}
 
-	_, err := ref.Set(ctx, m, firestore.MergeAll)
-	return err
+	op := operation{name: "Map.DeleteKeys", ref: f.Document.GetDocumentRef(), field: f.Name}
+	paths, err := f.keyPaths(keys)
+	if err != nil {
+		return f.opts.fail(ctx, op, err, op.attrs()...)
+	}
+
+	updates := make([]Update, len(keys))
+	for i, path := range paths {
+		updates[i] = Update{FieldPath: path, Value: Delete}
+	}
+
+	return f.opts.update(ctx, f.Document, op, updates, opts...)
+}
+
+// keyPaths returns the paths of the given keys of the Map field.
+func (f *Map) keyPaths(keys []string) ([]firestore.FieldPath, error) {
+	path, err := fieldPath(f.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]firestore.FieldPath, len(keys))
+	for i, key := range keys {
+		paths[i] = append(path[:len(path):len(path)], key)
+	}
+	return paths, nil
+}
+
+// Key returns a specific key of a Map field, keys containing dots or backticks being supported.
+//  city, err := fuego.Document("users", "jsmith").Map("Address").Key("City").String().Retrieve(ctx)
+func (f *Map) Key(key string) *MapKey {
+	return &MapKey{
+		Document:  f.Document,
+		Name:      f.Name + "." + internal.QuoteKey(key),
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
+}
+
+// MapKey represents a key of a Map field, which can be read and updated as any other field.
+type MapKey struct {
+
+	// Document is the underlying document (incl. ID and ref).
+	Document Document
+
+	// Name is the path of the key (e.g. "Address.City"), keys being quoted with backticks when needed.
+	Name string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// Key returns a specific key nested in the key.
+//  err := fuego.Document("users", "jsmith").Map("Settings").Key("notifications").Key("email").Boolean().Update(ctx, true)
+func (k *MapKey) Key(key string) *MapKey {
+	return k.Map().Key(key)
+}
+
+// String returns the key as a String field.
+func (k *MapKey) String() *String {
+	return &String{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
+}
+
+// Number returns the key as a Number field.
+func (k *MapKey) Number() *Number {
+	return &Number{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
+}
+
+// Float returns the key as a Float field.
+func (k *MapKey) Float() *Float {
+	return &Float{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
+}
+
+// Boolean returns the key as a Boolean field.
+func (k *MapKey) Boolean() *Boolean {
+	return &Boolean{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
+}
+
+// Timestamp returns the key as a Timestamp field.
+func (k *MapKey) Timestamp() *Timestamp {
+	return &Timestamp{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
+}
+
+// GeoPoint returns the key as a GeoPoint field.
+func (k *MapKey) GeoPoint() *GeoPoint {
+	return &GeoPoint{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
+}
+
+// Bytes returns the key as a Bytes field.
+func (k *MapKey) Bytes() *Bytes {
+	return &Bytes{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
+}
+
+// Reference returns the key as a Reference field.
+func (k *MapKey) Reference() *Reference {
+	return &Reference{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
+}
+
+// IsNull returns true if the key holds null, false if it holds another value or doesn't exist.
+//  null, err := fuego.Document("users", "jsmith").Map("Address").Key("Line2").IsNull(ctx)
+func (k *MapKey) IsNull(ctx context.Context) (bool, error) {
+	return isNull(ctx, "MapKey.IsNull", &TypedField[interface{}]{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts})
+}
+
+// SetNull sets the key to null.
+//  err := fuego.Document("users", "jsmith").Map("Address").Key("Line2").SetNull(ctx)
+func (k *MapKey) SetNull(ctx context.Context, opts ...WriteOption) error {
+	return k.opts.set(ctx, "MapKey.SetNull", k.Document, k.Name, nil, opts...)
+}
+
+// State returns the key as a State field, which transition history is stored in the key named after it followed by "History".
+func (k *MapKey) State(transitions Transitions) *State {
+	return &State{
+		Document:     k.Document,
+		Name:         k.Name,
+		Transitions:  transitions,
+		HistoryField: historyField(k.Name),
+		firestore:    k.firestore,
+		opts:         k.opts,
+	}
+}
+
+// Map returns the key as a (nested) Map field.
+func (k *MapKey) Map() *Map {
+	return &Map{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
+}
+
+// Array returns the key as an Array field.
+func (k *MapKey) Array() *Array {
+	return &Array{Document: k.Document, Name: k.Name, firestore: k.firestore, opts: k.opts}
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"cloud.google.com/go/firestore"
-	"github.com/remychantenay/fuego/document/internal"
 )
 
 // NumberField provides the necessary to interact with a Firestore document field of type Number.
@@ -14,7 +13,7 @@ type NumberField interface {
 	Retrieve(ctx context.Context) (int64, error)
 
 	// Update the value of a specific field containing a number (int64).
-	Update(ctx context.Context, with int64) error
+	Update(ctx context.Context, with int64, opts ...WriteOption) error
 
 	// Increment the value of a specific field containing a number (int64).
 	// If the field doesn't exist, it will be set to 1.
@@ -23,6 +22,25 @@ type NumberField interface {
 	// Decrement the value of a specific field containing a number (int64).
 	// If the field doesn't exist, it will be set to 0.
 	Decrement(ctx context.Context) error
+
+	// Add adds delta (possibly negative) to the value of a specific field containing a number (int64).
+	// If the field doesn't exist, it will be set to delta.
+	Add(ctx context.Context, delta int64, opts ...WriteOption) error
+
+	// Multiply multiplies the value of a specific field containing a number (int64) by factor.
+	// If the field doesn't exist, it will be set to 0.
+	Multiply(ctx context.Context, factor int64, opts ...WriteOption) error
+
+	// Min sets a specific field containing a number (int64) to the minimum of its value and the given value.
+	// If the field doesn't exist, it will be set to the given value.
+	Min(ctx context.Context, with int64, opts ...WriteOption) error
+
+	// Max sets a specific field containing a number (int64) to the maximum of its value and the given value.
+	// If the field doesn't exist, it will be set to the given value.
+	Max(ctx context.Context, with int64, opts ...WriteOption) error
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
 }
 
 // Number represents a document field of type Number.
@@ -35,97 +53,98 @@ type Number struct {
 	Name string
 
 	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *Number) field() *TypedField[int64] {
+	return &TypedField[int64]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
 }
 
 // Retrieve returns the content of a specific field for a given document.
 //  nb, err := fuego.Document("users", "jsmith").Number("Age").Retrieve(ctx)
 func (f *Number) Retrieve(ctx context.Context) (int64, error) {
-	value, err := internal.RetrieveFieldValue(ctx, f.Document.GetDocumentRef(), f.Name)
-	if err != nil {
-		return 0, err
-	}
-
-	return value.(int64), nil
+	return f.field().get(ctx, "Number.Retrieve")
 }
 
 // Update the value of a specific field of type Number.
 //  err := fuego.Document("users", "jsmith").Number("Age").Update(ctx, 42).
-func (f *Number) Update(ctx context.Context, with int64) error {
-
-	ref := f.Document.GetDocumentRef()
-	m := map[string]int64{
-		f.Name: with,
-	}
-
-	if f.Document.InBatch() {
-		f.Document.Batch().Set(ref, m, firestore.MergeAll)
-		return nil
-	}
-
-	_, err := ref.Set(ctx, m, firestore.MergeAll)
-	return err
+func (f *Number) Update(ctx context.Context, with int64, opts ...WriteOption) error {
+	return f.field().set(ctx, "Number.Update", with, opts...)
 }
 
 // Increment the value of a specific field of type Number.
 //
-// The update will be executed inside a transaction.
+// The update will be executed inside a transaction, see Add for an update applied by Firestore.
 // If the field doesn't exist, it will be set to 1.
 //  err := fuego.Document("users", "jsmith").Number("Age").Increment(ctx)
 func (f *Number) Increment(ctx context.Context) error {
-
-	return f.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
-
-		ref := f.Document.GetDocumentRef()
-		document, err := tx.Get(ref)
-		if err != nil {
-			return err
-		}
-
-		newValue := int64(1)
-		value, err := document.DataAt(f.Name)
-		if err == nil {
-			newValue = value.(int64) + 1
-		}
-
-		err = tx.Set(ref, map[string]int64{
-			f.Name: newValue,
-		}, firestore.MergeAll)
-		if err != nil {
-			return err
-		}
-
-		return nil // Success, no errors
+	return f.field().modify(ctx, "Number.Increment", func(current int64, exists bool) (int64, bool, error) {
+		return current + 1, true, nil
 	})
 }
 
 // Decrement the value of a specific field of type Number.
 //
-// The update will be executed inside a transaction.
+// The update will be executed inside a transaction, see Add for an update applied by Firestore.
 // If the field doesn't exist, it will be set to 0.
 //  err := fuego.Document("users", "jsmith").Number("Age").Decrement(ctx)
 func (f *Number) Decrement(ctx context.Context) error {
+	return f.field().modify(ctx, "Number.Decrement", func(current int64, exists bool) (int64, bool, error) {
+		if !exists {
+			return 0, true, nil
+		}
+		return current - 1, true, nil
+	})
+}
 
-	return f.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+// Add adds delta (possibly negative) to the value of a specific field of type Number.
+//
+// The update is applied by Firestore: no transaction is needed (i.e. no contention between concurrent updates),
+// which also makes it usable in a write batch. It is not retried on failure, as it may have been applied.
+// If the field doesn't exist, it will be set to delta.
+//  err := fuego.Document("users", "jsmith").Number("Points").Add(ctx, 10)
+func (f *Number) Add(ctx context.Context, delta int64, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Number.Add", f.Document, f.Name, firestore.Increment(delta), opts...)
+}
 
-		ref := f.Document.GetDocumentRef()
-		document, err := tx.Get(ref)
-		if err != nil {
-			return err
-		}
+// Multiply multiplies the value of a specific field of type Number by factor.
+//
+// The update will be executed inside a transaction.
+// If the field doesn't exist, it will be set to 0.
+//  err := fuego.Document("users", "jsmith").Number("Points").Multiply(ctx, 2)
+func (f *Number) Multiply(ctx context.Context, factor int64, opts ...WriteOption) error {
+	return f.field().modify(ctx, "Number.Multiply", func(current int64, exists bool) (int64, bool, error) {
+		return current * factor, true, nil
+	}, opts...)
+}
 
-		newValue := int64(0)
-		value, err := document.DataAt(f.Name)
-		if err == nil {
-			newValue = value.(int64) - 1
-		}
+// Min sets a specific field of type Number to the minimum of its value and the given value (i.e. caps it).
+//
+// The update is applied by Firestore (see Add).
+// If the field doesn't exist, it will be set to the given value.
+//  err := fuego.Document("users", "jsmith").Number("Lives").Min(ctx, 3)
+func (f *Number) Min(ctx context.Context, with int64, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Number.Min", f.Document, f.Name, firestore.FieldTransformMinimum(with), opts...)
+}
 
-		err = tx.Set(ref, map[string]int64{
-			f.Name: newValue,
-		}, firestore.MergeAll)
-		if err != nil {
-			return err
-		}
+// Max sets a specific field of type Number to the maximum of its value and the given value (i.e. floors it).
+//
+// The update is applied by Firestore (see Add).
+// If the field doesn't exist, it will be set to the given value.
+//  err := fuego.Document("users", "jsmith").Number("HighScore").Max(ctx, score)
+func (f *Number) Max(ctx context.Context, with int64, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Number.Max", f.Document, f.Name, firestore.FieldTransformMaximum(with), opts...)
+}
 
-		return nil // Success, no errors
-	})
+// Delete removes a specific field of type Number from the document.
+//  err := fuego.Document("users", "jsmith").Number("Age").Delete(ctx)
+func (f *Number) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Number.Delete", f.Document, f.Name, Delete, opts...)
 }
@@ -0,0 +1,269 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/remychantenay/fuego/document/internal"
+	"github.com/remychantenay/fuego/internal/errs"
+	"github.com/remychantenay/fuego/internal/ratelimit"
+	"github.com/remychantenay/fuego/internal/retry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// operation describes an operation performed on a document or on one of its fields.
+type operation struct {
+
+	// name is the name of the operation (e.g. "String.Update").
+	name string
+
+	// ref is the reference of the document.
+	ref *firestore.DocumentRef
+
+	// field is the name of the field (if any).
+	field string
+
+	// idem tells whether the operation can safely be retried.
+	idem retry.Idempotency
+
+	// conditional tells whether the operation is a write with preconditions.
+	conditional bool
+}
+
+// attrs returns the log attributes describing the operation.
+func (op operation) attrs(attrs ...slog.Attr) []slog.Attr {
+	if len(op.field) == 0 {
+		return attrs
+	}
+	return append([]slog.Attr{slog.String("field", op.field)}, attrs...)
+}
+
+// wrap classifies err and adds the context of the operation.
+//
+// The gRPC code of err (if any) is kept when it is reclassified (e.g. a FailedPrecondition as a Conflict),
+// the other Conflict errors have the FailedPrecondition code: none of them is worth retrying.
+func (op operation) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *errs.Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	var fieldNotFound *firestore.FieldNotFoundError
+	var typeErr *internal.TypeError
+	var pathErr *internal.PathError
+	switch {
+	case errors.As(err, &pathErr):
+		return errs.New(op.name, op.ref.Path, op.field, errs.InvalidArgument, err)
+	case errors.Is(err, ErrPreconditionFailed), errors.Is(err, ErrIllegalTransition):
+		return errs.New(op.name, op.ref.Path, op.field, errs.Conflict, err)
+	case op.conditional && (status.Code(err) == codes.FailedPrecondition || status.Code(err) == codes.NotFound):
+		err = fmt.Errorf("%w: %w", ErrPreconditionFailed, err)
+		return errs.New(op.name, op.ref.Path, op.field, errs.Conflict, err)
+	case errors.Is(err, ErrPreconditionNotSupported), errors.Is(err, ErrIndexOutOfRange), errors.Is(err, ErrInvalidLength),
+		errors.Is(err, ErrSizeLimitExceeded), errors.Is(err, ErrNoClient):
+		return errs.New(op.name, op.ref.Path, op.field, errs.InvalidArgument, err)
+	case errors.As(err, &typeErr):
+		return errs.New(op.name, op.ref.Path, op.field, errs.TypeMismatch, err)
+	case errors.As(err, &fieldNotFound):
+		err = fmt.Errorf("%w: %w", ErrFieldRetrieve, err)
+		return errs.New(op.name, op.ref.Path, op.field, errs.NotFound, err)
+	case status.Code(err) == codes.NotFound && !errors.Is(err, ErrDocumentNotExist):
+		err = fmt.Errorf("%w: %w", ErrDocumentNotExist, err)
+		return errs.New(op.name, op.ref.Path, op.field, errs.NotFound, err)
+	case errors.Is(err, ErrDocumentNotExist):
+		return errs.New(op.name, op.ref.Path, op.field, errs.NotFound, err)
+	}
+
+	return errs.Wrap(op.name, op.ref.Path, op.field, err)
+}
+
+// run executes a direct operation, retries it according to its policy and logs its outcome.
+//
+// Each attempt is subject to rate limiting. The returned error (if any) is an *errs.Error.
+func (o *options) run(ctx context.Context, op operation, fn func(ctx context.Context) error, attrs ...slog.Attr) error {
+	attrs = op.attrs(attrs...)
+	o.logger().Operation(ctx, op.name, op.ref.Path, attrs...)
+
+	attempt := func(ctx context.Context) error {
+		if err := ratelimit.Wait(ctx, o.rateLimiter(), 1); err != nil {
+			return err
+		}
+		return fn(ctx)
+	}
+
+	err := o.retryPolicy(op.name).Do(ctx, op.idem, attempt, func(attempt int, wait time.Duration, err error) {
+		o.logger().Retry(ctx, op.name, op.ref.Path, attempt, wait, err)
+	})
+	if err != nil {
+		return o.fail(ctx, op, err, attrs...)
+	}
+
+	return nil
+}
+
+// fail classifies the error of an operation, logs it and returns it.
+//
+// attrs are expected to already describe the operation (see operation.attrs).
+func (o *options) fail(ctx context.Context, op operation, err error, attrs ...slog.Attr) error {
+	err = op.wrap(err)
+	o.logger().Failure(ctx, op.name, op.ref.Path, err, attrs...)
+	return err
+}
+
+// batched logs an operation added to a write batch.
+func (o *options) batched(ctx context.Context, op operation, attrs ...slog.Attr) {
+	o.logger().Operation(ctx, op.name, op.ref.Path, op.attrs(append(attrs, slog.Bool("batch", true))...)...)
+}
+
+// transacted logs an operation added to a transaction.
+func (o *options) transacted(ctx context.Context, op operation, attrs ...slog.Attr) {
+	o.logger().Operation(ctx, op.name, op.ref.Path, op.attrs(append(attrs, slog.Bool("transaction", true))...)...)
+}
+
+// writer performs a write in each of the modes supported by the documents.
+type writer struct {
+
+	// direct performs the write directly.
+	direct func(ctx context.Context) error
+
+	// batch adds the write to a write batch.
+	batch func(wb *firestore.WriteBatch) error
+
+	// tx adds the write to a transaction.
+	tx func(tx *firestore.Transaction) error
+}
+
+// write adds a write to the document's transaction or write batch (if any), or performs it directly.
+func (o *options) write(ctx context.Context, doc Document, op operation, w writer, attrs ...slog.Attr) error {
+	if doc.InTransaction() {
+		o.transacted(ctx, op, attrs...)
+		if err := w.tx(doc.Transaction()); err != nil {
+			return o.fail(ctx, op, err, op.attrs(attrs...)...)
+		}
+		return nil
+	}
+
+	if doc.InBatch() {
+		o.batched(ctx, op, attrs...)
+		if err := w.batch(doc.Batch()); err != nil {
+			return o.fail(ctx, op, err, op.attrs(attrs...)...)
+		}
+		return nil
+	}
+
+	return o.run(ctx, op, w.direct, attrs...)
+}
+
+// snapshot reads a document, within the transaction (if any).
+func (o *options) snapshot(ctx context.Context, ref *firestore.DocumentRef) (*firestore.DocumentSnapshot, error) {
+	if tx := o.transaction(); tx != nil {
+		return tx.Get(ref)
+	}
+	return ref.Get(ctx)
+}
+
+// runTransaction executes fn inside a transaction and logs its outcome, retries included.
+//
+// fn is executed inside the on-going transaction (if any), a new transaction is run otherwise.
+// Transactions are considered non-idempotent: the commit may have been applied even though an error is returned.
+func (o *options) runTransaction(ctx context.Context, fs *firestore.Client, op operation, fn func(ctx context.Context, tx *firestore.Transaction) error) error {
+	if tx := o.transaction(); tx != nil {
+		o.transacted(ctx, op)
+		if err := fn(ctx, tx); err != nil {
+			return o.fail(ctx, op, err, op.attrs()...)
+		}
+		return nil
+	}
+
+	if fs == nil {
+		return o.fail(ctx, op, ErrNoClient, op.attrs()...)
+	}
+
+	op.idem = retry.NonIdempotent
+	return o.run(ctx, op, func(ctx context.Context) error {
+		attempt := 0
+		return fs.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			attempt++
+			if attempt > 1 {
+				o.logger().TransactionRetry(ctx, op.name, op.ref.Path, attempt)
+			}
+			return fn(ctx, tx)
+		})
+	})
+}
+
+// set sets the value of a (possibly nested) field of an existing document.
+//
+// The write is added to the document's transaction or write batch if one has been started.
+func (o *options) set(ctx context.Context, name string, doc Document, field string, value interface{}, opts ...WriteOption) error {
+	op := operation{name: name, ref: doc.GetDocumentRef(), field: field}
+
+	path, err := fieldPath(field)
+	if err != nil {
+		return o.fail(ctx, op, err, op.attrs()...)
+	}
+
+	return o.update(ctx, doc, op, []Update{{FieldPath: path, Value: value}}, opts...)
+}
+
+// update applies updates to an existing document, in a single write.
+//
+// The write is added to the document's transaction or write batch if one has been started.
+func (o *options) update(ctx context.Context, doc Document, op operation, updates []Update, opts ...WriteOption) error {
+	_, err := o.updateResult(ctx, doc, op, updates, opts...)
+	return err
+}
+
+// updateResult applies updates to an existing document, in a single write, and returns the result of the write.
+//
+// The write is added to the document's transaction or write batch if one has been started,
+// in which case the result is nil (it is only known once committed).
+func (o *options) updateResult(ctx context.Context, doc Document, op operation, updates []Update, opts ...WriteOption) (*firestore.WriteResult, error) {
+	w := newWriteOptions(opts...)
+	op.conditional = w.conditional()
+	if !idempotent(updates) {
+		op.idem = retry.NonIdempotent
+	}
+
+	var attr slog.Attr
+	if len(updates) == 1 {
+		attr = o.logger().Value(updatePath(updates[0]), updates[0].Value)
+	} else {
+		fields := make([]string, len(updates))
+		values := make([]interface{}, len(updates))
+		for i, u := range updates {
+			fields[i], values[i] = updatePath(u), u.Value
+		}
+		attr = o.logger().Values(fields, values)
+	}
+
+	var result *firestore.WriteResult
+	err := o.write(ctx, doc, op, writer{
+		direct: func(ctx context.Context) error {
+			var err error
+			result, err = op.ref.Update(ctx, updates, w.update()...)
+			return err
+		},
+		batch: func(wb *firestore.WriteBatch) error {
+			wb.Update(op.ref, updates, w.update()...)
+			return nil
+		},
+		tx: func(tx *firestore.Transaction) error {
+			return tx.Update(op.ref, updates, w.update()...)
+		},
+	}, attr)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package document
+
+import (
+	"log/slog"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/remychantenay/fuego/document/internal"
+	"github.com/remychantenay/fuego/internal/ids"
+	"github.com/remychantenay/fuego/internal/location"
+	"github.com/remychantenay/fuego/internal/logging"
+	"github.com/remychantenay/fuego/internal/ratelimit"
+	"github.com/remychantenay/fuego/internal/retry"
+)
+
+// Option configures a FirestoreDocument.
+type Option func(*options)
+
+// Conversion is a set of lenient conversions applied when retrieving typed field values.
+//
+// By default, a field value of an unexpected type results in a TypeMismatch error.
+type Conversion = internal.Conversion
+
+const (
+	// ConvertIntegralFloats converts doubles without fractional part (e.g. 42.0) to integers.
+	ConvertIntegralFloats = internal.ConvertIntegralFloats
+
+	// ConvertNumericStrings converts strings holding a number (e.g. "42") to numbers.
+	ConvertNumericStrings = internal.ConvertNumericStrings
+
+	// ConvertNullToZero converts null values to the zero value of the expected type.
+	ConvertNullToZero = internal.ConvertNullToZero
+
+	// LenientConversions enables all the conversions.
+	LenientConversions = ConvertIntegralFloats | ConvertNumericStrings | ConvertNullToZero
+)
+
+// WithLogger sets the logger used by the document and its fields (nothing is logged by default).
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.slog = l
+	}
+}
+
+// WithLogRedaction sets how field values appear in the logs (all of them are redacted by default).
+func WithLogRedaction(r func(field string, value interface{}) interface{}) Option {
+	return func(o *options) {
+		o.redact = r
+	}
+}
+
+// WithRetryPolicies sets the retry policies applied to the direct (i.e. not batched) operations.
+func WithRetryPolicies(p *retry.Policies) Option {
+	return func(o *options) {
+		o.retry = p
+	}
+}
+
+// WithLimiter sets the limiter throttling the direct (i.e. not batched) operations.
+func WithLimiter(l ratelimit.Limiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// WithConversions sets the lenient conversions applied when retrieving typed field values.
+func WithConversions(c Conversion) Option {
+	return func(o *options) {
+		o.conversions = c
+	}
+}
+
+// WithIDStrategy sets the strategy generating the ID of the document when none is provided.
+//
+// By default, IDs are generated the same way as Firestore does.
+func WithIDStrategy(s ids.Strategy) Option {
+	return func(o *options) {
+		o.ids = s
+	}
+}
+
+// WithTransaction sets the transaction the operations of the document and its fields are part of.
+//
+// Reads are performed within the transaction and writes are added to it.
+func WithTransaction(tx *firestore.Transaction) Option {
+	return func(o *options) {
+		o.tx = tx
+	}
+}
+
+// WithLocations sets the cache of the locations used to retrieve the Timestamp fields.
+func WithLocations(c *location.Cache) Option {
+	return func(o *options) {
+		o.locations = c
+	}
+}
+
+// options holds the client-wide settings shared by a document and its fields.
+//
+// A nil *options is valid and falls back to the defaults.
+type options struct {
+	slog    *slog.Logger
+	redact  logging.Redactor
+	log     *logging.Logger
+	retry   *retry.Policies
+	limiter ratelimit.Limiter
+
+	conversions Conversion
+
+	ids ids.Strategy
+
+	tx *firestore.Transaction
+
+	locations *location.Cache
+}
+
+// newOptions creates and returns options with the provided Option(s) applied.
+func newOptions(opts ...Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.log = logging.New(o.slog, o.redact)
+	return o
+}
+
+// logger returns the logger (if any), nil otherwise.
+func (o *options) logger() *logging.Logger {
+	if o == nil {
+		return nil
+	}
+	return o.log
+}
+
+// retryPolicy returns the retry policy applying to a given operation.
+func (o *options) retryPolicy(op string) retry.Policy {
+	if o == nil {
+		return retry.Policy{}
+	}
+	return o.retry.For(op)
+}
+
+// rateLimiter returns the limiter (if any), nil otherwise.
+func (o *options) rateLimiter() ratelimit.Limiter {
+	if o == nil {
+		return nil
+	}
+	return o.limiter
+}
+
+// conversion returns the lenient conversions enabled (if any).
+func (o *options) conversion() Conversion {
+	if o == nil {
+		return 0
+	}
+	return o.conversions
+}
+
+// idStrategy returns the strategy generating the IDs of the documents.
+func (o *options) idStrategy() ids.Strategy {
+	if o == nil || o.ids == nil {
+		return ids.Auto
+	}
+	return o.ids
+}
+
+// transaction returns the transaction (if any), nil otherwise.
+func (o *options) transaction() *firestore.Transaction {
+	if o == nil {
+		return nil
+	}
+	return o.tx
+}
+
+// location returns the location with the given name, from the cache (if any).
+func (o *options) location(name string) (*time.Location, error) {
+	if o == nil {
+		return time.LoadLocation(name)
+	}
+	return o.locations.Load(name)
+}
@@ -0,0 +1,99 @@
+package document
+
+import (
+	"context"
+	"reflect"
+
+	"cloud.google.com/go/firestore"
+)
+
+// ReferenceField provides the necessary to interact with a Firestore document field of type Reference.
+type ReferenceField interface {
+
+	// Retrieve returns the document referenced by a specific field.
+	Retrieve(ctx context.Context) (*FirestoreDocument, error)
+
+	// Update updates the document referenced by a specific field.
+	Update(ctx context.Context, with Document, opts ...WriteOption) error
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
+}
+
+// Reference represents a document field of type Reference.
+type Reference struct {
+
+	// Document is the underlying document (incl. ID and ref).
+	Document Document
+
+	// Name is the name of the field.
+	Name string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *Reference) field() *TypedField[*firestore.DocumentRef] {
+	return &TypedField[*firestore.DocumentRef]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
+}
+
+// Retrieve returns the document referenced by a specific field, which can be used as any other document
+// (it shares the settings, the write batch and the transaction of the document holding the field).
+//
+// nil is returned if the field is null and the ConvertNullToZero conversion is enabled.
+//  manager, err := fuego.Document("users", "jsmith").Reference("Manager").Retrieve(ctx)
+//  err = manager.Retrieve(ctx, &user)
+func (f *Reference) Retrieve(ctx context.Context) (*FirestoreDocument, error) {
+	ref, err := f.field().get(ctx, "Reference.Retrieve")
+	if err != nil || ref == nil {
+		return nil, err
+	}
+
+	return &FirestoreDocument{
+		ColRef:     ref.Parent,
+		ID:         ref.ID,
+		writeBatch: f.Document.Batch(),
+		firestore:  f.firestore,
+		opts:       f.opts,
+	}, nil
+}
+
+// Update updates the document referenced by a specific field of type Reference.
+//
+// The field is set to null if with is nil (including a nil *FirestoreDocument).
+//  err := fuego.Document("users", "jsmith").Reference("Manager").Update(ctx, fuego.Document("users", "enorton"))
+func (f *Reference) Update(ctx context.Context, with Document, opts ...WriteOption) error {
+	var ref *firestore.DocumentRef
+	if !isNil(with) {
+		ref = with.GetDocumentRef()
+	}
+
+	return f.field().set(ctx, "Reference.Update", ref, opts...)
+}
+
+// Delete removes a specific field of type Reference from the document.
+//  err := fuego.Document("users", "jsmith").Reference("Manager").Delete(ctx)
+func (f *Reference) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Reference.Delete", f.Document, f.Name, Delete, opts...)
+}
+
+// isNil returns true if d is nil or holds a nil value (e.g. a nil *FirestoreDocument).
+func isNil(d Document) bool {
+	if d == nil {
+		return true
+	}
+
+	switch v := reflect.ValueOf(d); v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
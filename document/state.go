@@ -0,0 +1,190 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/remychantenay/fuego/document/internal"
+)
+
+// Transitions is the graph of the transitions allowed between states: the states reachable from each state.
+//
+// A missing field being in the "" state, the initial states are the ones reachable from "".
+//  transitions := document.Transitions{
+//  	"":           {"pending"},
+//  	"pending":    {"processing", "cancelled"},
+//  	"processing": {"shipped", "cancelled"},
+//  }
+type Transitions map[string][]string
+
+// Allowed returns true if the transition from a state to another is allowed.
+func (t Transitions) Allowed(from, to string) bool {
+	return slices.Contains(t[from], to)
+}
+
+// StateTransition is an entry of the transition history of a State field.
+type StateTransition struct {
+
+	// From is the state before the transition.
+	From string `firestore:"From"`
+
+	// To is the state after the transition.
+	To string `firestore:"To"`
+
+	// At is the time of the transition, according to the clock of the client.
+	At time.Time `firestore:"At"`
+}
+
+// TransitionError indicates that a transition isn't allowed from the current state.
+//
+// It matches ErrIllegalTransition (with errors.Is).
+type TransitionError struct {
+
+	// From is the current state.
+	From string
+
+	// To is the requested state.
+	To string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: from %q to %q", ErrIllegalTransition, e.From, e.To)
+}
+
+// Unwrap returns ErrIllegalTransition.
+func (e *TransitionError) Unwrap() error {
+	return ErrIllegalTransition
+}
+
+// StateField provides the necessary to interact with a Firestore document field of type String holding a state.
+type StateField interface {
+
+	// Retrieve returns the current state.
+	Retrieve(ctx context.Context) (string, error)
+
+	// Transition moves to a given state, if the transition is allowed from the current state.
+	Transition(ctx context.Context, to string) error
+
+	// History returns the transition history.
+	History(ctx context.Context) ([]StateTransition, error)
+}
+
+// State represents a document field of type String holding a state, which only changes according to declared transitions.
+//
+// The transition history is stored in the Array field named HistoryField.
+type State struct {
+
+	// Document is the underlying document (incl. ID and ref).
+	Document Document
+
+	// Name is the name of the field.
+	Name string
+
+	// Transitions are the transitions allowed between states.
+	Transitions Transitions
+
+	// HistoryField is the name of the field holding the transition history
+	// (by default, Name with its last key followed by "History").
+	HistoryField string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// state returns the field holding the state.
+func (f *State) state() *TypedField[string] {
+	return &TypedField[string]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
+}
+
+// history returns the field holding the transition history.
+func (f *State) history() *TypedField[[]StateTransition] {
+	return &TypedField[[]StateTransition]{
+		Document:  f.Document,
+		Name:      f.HistoryField,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
+}
+
+// Retrieve returns the current state, "" if the field doesn't exist.
+//  status, err := fuego.Document("orders", "123").State("Status", transitions).Retrieve(ctx)
+func (f *State) Retrieve(ctx context.Context) (string, error) {
+	value, _, err := f.state().lookup(ctx, "State.Retrieve")
+	return value, err
+}
+
+// Transition moves to a given state, if the transition is allowed from the current state.
+//
+// The state and a new entry of the transition history are written atomically, inside a transaction.
+// The history isn't capped: it grows with each transition, within the max. size of a document (1 MiB).
+// A Conflict error wrapping a *TransitionError (matching ErrIllegalTransition) is returned if the transition isn't allowed.
+// Its gRPC code is FailedPrecondition: it isn't retried, including when returned from a transaction.
+//  err := fuego.Document("orders", "123").State("Status", transitions).Transition(ctx, "processing")
+func (f *State) Transition(ctx context.Context, to string) error {
+	op := operation{name: "State.Transition", ref: f.Document.GetDocumentRef(), field: f.Name}
+
+	state, err := fieldPath(f.Name)
+	if err != nil {
+		return f.opts.fail(ctx, op, err, op.attrs()...)
+	}
+
+	history, err := fieldPath(f.HistoryField)
+	if err != nil {
+		return f.opts.fail(ctx, op, err, op.attrs()...)
+	}
+
+	return f.opts.runTransaction(ctx, f.firestore, op, func(ctx context.Context, tx *firestore.Transaction) error {
+		s, err := tx.Get(op.ref)
+		if err != nil {
+			return err
+		}
+
+		from, _, err := f.state().decodeIfExists(s)
+		if err != nil {
+			return err
+		}
+
+		if !f.Transitions.Allowed(from, to) {
+			return &TransitionError{From: from, To: to}
+		}
+
+		entries, _, err := f.history().decodeIfExists(s)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, StateTransition{From: from, To: to, At: time.Now().UTC()})
+		return tx.Update(op.ref, []Update{
+			{FieldPath: state, Value: to},
+			{FieldPath: history, Value: entries},
+		})
+	})
+}
+
+// History returns the transition history, oldest first (empty if there has been no transition).
+//  history, err := fuego.Document("orders", "123").State("Status", transitions).History(ctx)
+func (f *State) History(ctx context.Context) ([]StateTransition, error) {
+	value, _, err := f.history().lookup(ctx, "State.History")
+	return value, err
+}
+
+// historyField returns the name of the field holding the transition history of the field with the given name:
+// its last key followed by "History" (e.g. "Order.`web.statusHistory`" for "Order.`web.status`").
+func historyField(name string) string {
+	path, err := fieldPath(name)
+	if err != nil {
+		return name + "History" // the name is invalid, which is reported when the field is used
+	}
+
+	path[len(path)-1] += "History"
+	return internal.JoinPath(path...)
+}
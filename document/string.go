@@ -2,9 +2,9 @@ package document
 
 import (
 	"context"
+	"slices"
 
 	"cloud.google.com/go/firestore"
-	"github.com/remychantenay/fuego/document/internal"
 )
 
 // StringField provides the necessary to interact with a Firestore document field of type String.
@@ -14,7 +14,18 @@ type StringField interface {
 	Retrieve(ctx context.Context) (string, error)
 
 	// Update updates the value of a specific field containing a string.
-	Update(ctx context.Context, with string) error
+	Update(ctx context.Context, with string, opts ...WriteOption) error
+
+	// CompareAndSwap sets the value of a specific field containing a string only if it currently holds old,
+	// and returns true if the swap happened.
+	CompareAndSwap(ctx context.Context, old, new string) (bool, error)
+
+	// UpdateIfIn sets the value of a specific field containing a string only if it currently holds one of the allowed values,
+	// and returns true if it has been set.
+	UpdateIfIn(ctx context.Context, allowed []string, with string) (bool, error)
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
 }
 
 // String represents a document field of type String.
@@ -25,32 +36,64 @@ type String struct {
 
 	// Name is the name of the field.
 	Name string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *String) field() *TypedField[string] {
+	return &TypedField[string]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
 }
 
 // Retrieve returns the content of a specific field for a given document.
 //  str, err := fuego.Document("users", "jsmith").String("FirstName").Retrieve(ctx)
 func (f *String) Retrieve(ctx context.Context) (string, error) {
-	value, err := internal.RetrieveFieldValue(ctx, f.Document.GetDocumentRef(), f.Name)
-	if err != nil {
-		return "", err
-	}
-
-	return value.(string), nil
+	return f.field().get(ctx, "String.Retrieve")
 }
 
 // Update updates the value of a specific field of type String.
 //  err := fuego.Document("users", "jsmith").String("FirstName").Update(ctx, "Jane")
-func (f *String) Update(ctx context.Context, with string) error {
+func (f *String) Update(ctx context.Context, with string, opts ...WriteOption) error {
+	return f.field().set(ctx, "String.Update", with, opts...)
+}
 
-	ref := f.Document.GetDocumentRef()
-	m := map[string]string{
-		f.Name: with,
-	}
+// CompareAndSwap sets the value of a specific field of type String only if it currently holds old,
+// and returns true if the swap happened. A missing field is considered as holding an empty string.
+//
+// The comparison and the update will be executed inside a transaction.
+//  swapped, err := fuego.Document("orders", "123").String("Status").CompareAndSwap(ctx, "pending", "processing")
+func (f *String) CompareAndSwap(ctx context.Context, old, new string) (bool, error) {
+	return f.field().compareAndSwap(ctx, "String.CompareAndSwap", old, new)
+}
 
-	if f.Document.InBatch() {
-		f.Document.Batch().Set(ref, m, firestore.MergeAll)
-		return nil
+// UpdateIfIn sets the value of a specific field of type String only if it currently holds one of the allowed values
+// (e.g. the states from which a transition is allowed), and returns true if it has been set.
+// A missing field is considered as holding an empty string.
+//
+// The comparison and the update will be executed inside a transaction.
+//  updated, err := fuego.Document("orders", "123").String("Status").UpdateIfIn(ctx, []string{"pending", "processing"}, "cancelled")
+func (f *String) UpdateIfIn(ctx context.Context, allowed []string, with string) (bool, error) {
+	updated := false
+	err := f.field().modify(ctx, "String.UpdateIfIn", func(current string, exists bool) (string, bool, error) {
+		updated = slices.Contains(allowed, current)
+		return with, updated, nil
+	})
+	if err != nil {
+		return false, err
 	}
-	_, err := ref.Set(ctx, m, firestore.MergeAll)
-	return err
+
+	return updated, nil
+}
+
+// Delete removes a specific field of type String from the document.
+//  err := fuego.Document("users", "jsmith").String("FirstName").Delete(ctx)
+func (f *String) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "String.Delete", f.Document, f.Name, Delete, opts...)
 }
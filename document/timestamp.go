@@ -2,10 +2,11 @@ package document
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"cloud.google.com/go/firestore"
-	"github.com/remychantenay/fuego/document/internal"
+	"github.com/remychantenay/fuego/internal/errs"
 )
 
 // TimestampField provides the necessary to interact with a Firestore document field of type Timestamp.
@@ -17,8 +18,21 @@ type TimestampField interface {
 	// A time.Time zero value will be returned if an error occurs.
 	Retrieve(ctx context.Context, location string) (time.Time, error)
 
+	// RetrieveIn returns the value of a specific field containing a timestamp (time.Time) in the given location.
+	RetrieveIn(ctx context.Context, loc *time.Location) (time.Time, error)
+
+	// RetrieveUTC returns the value of a specific field containing a timestamp (time.Time) in UTC.
+	RetrieveUTC(ctx context.Context) (time.Time, error)
+
 	// Update updates the value of a specific field containing a timestamp (time.Time).
-	Update(ctx context.Context, with time.Time) error
+	Update(ctx context.Context, with time.Time, opts ...WriteOption) error
+
+	// Touch sets the value of a specific field containing a timestamp (time.Time) to the time of the write
+	// (i.e. the server time) and returns it.
+	Touch(ctx context.Context, opts ...WriteOption) (time.Time, error)
+
+	// Delete removes a specific field from the document.
+	Delete(ctx context.Context, opts ...WriteOption) error
 }
 
 // Timestamp represents a document field of type Timestamp.
@@ -29,40 +43,97 @@ type Timestamp struct {
 
 	// Name is the name of the field.
 	Name string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// field returns the typed field backing f.
+func (f *Timestamp) field() *TypedField[time.Time] {
+	return &TypedField[time.Time]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
 }
 
 // Retrieve returns the content of a specific field for a given document.
 //
-// location needs to be a value from the IANA Time Zone database
+// location needs to be a value from the IANA Time Zone database, the locations being cached by the client.
 // A time.Time zero value will be returned if an error occurs.
 //  val, err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").Retrieve(ctx, "America/Los_Angeles")
 func (f *Timestamp) Retrieve(ctx context.Context, location string) (time.Time, error) {
-	value, err := internal.RetrieveFieldValue(ctx, f.Document.GetDocumentRef(), f.Name)
+	loc, err := f.opts.location(location)
 	if err != nil {
-		return time.Time{}, err
+		return time.Time{}, errs.New("Timestamp.Retrieve", f.Document.GetDocumentRef().Path, f.Name, errs.InvalidArgument, err)
 	}
 
-	loc, err := time.LoadLocation(location)
+	return f.retrieve(ctx, "Timestamp.Retrieve", loc)
+}
+
+// RetrieveIn returns the content of a specific field for a given document, in the given location.
+//
+// A time.Time zero value will be returned if an error occurs.
+//  loc, _ := time.LoadLocation("America/Los_Angeles")
+//  val, err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").RetrieveIn(ctx, loc)
+func (f *Timestamp) RetrieveIn(ctx context.Context, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		err := errors.New("nil location")
+		return time.Time{}, errs.New("Timestamp.RetrieveIn", f.Document.GetDocumentRef().Path, f.Name, errs.InvalidArgument, err)
+	}
+
+	return f.retrieve(ctx, "Timestamp.RetrieveIn", loc)
+}
+
+// RetrieveUTC returns the content of a specific field for a given document, in UTC.
+//
+// A time.Time zero value will be returned if an error occurs.
+//  val, err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").RetrieveUTC(ctx)
+func (f *Timestamp) RetrieveUTC(ctx context.Context) (time.Time, error) {
+	return f.retrieve(ctx, "Timestamp.RetrieveUTC", time.UTC)
+}
+
+// retrieve returns the content of the field in the given location.
+func (f *Timestamp) retrieve(ctx context.Context, name string, loc *time.Location) (time.Time, error) {
+	value, err := f.field().get(ctx, name)
 	if err != nil {
 		return time.Time{}, err
 	}
-	return value.(time.Time).In(loc), nil
+	return value.In(loc), nil
 }
 
 // Update updates the value of a specific field of type Timestamp.
 //  err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").Update(ctx, time.Now())
-func (f *Timestamp) Update(ctx context.Context, with time.Time) error {
+func (f *Timestamp) Update(ctx context.Context, with time.Time, opts ...WriteOption) error {
+	return f.field().set(ctx, "Timestamp.Update", with, opts...)
+}
+
+// Touch sets the value of a specific field of type Timestamp to the time at which the write is applied by Firestore
+// (i.e. ServerTimestamp), which doesn't depend on the clock of the client, and returns it.
+//
+// In a write batch or a transaction, the write is only applied once committed: the zero time is returned
+// and the time of the write is the UpdateTime of its result (e.g. as returned by CommitBatch).
+//  lastSeenAt, err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").Touch(ctx)
+func (f *Timestamp) Touch(ctx context.Context, opts ...WriteOption) (time.Time, error) {
+	op := operation{name: "Timestamp.Touch", ref: f.Document.GetDocumentRef(), field: f.Name}
 
-	ref := f.Document.GetDocumentRef()
-	m := map[string]interface{}{
-		f.Name: with,
+	path, err := fieldPath(f.Name)
+	if err != nil {
+		return time.Time{}, f.opts.fail(ctx, op, err, op.attrs()...)
 	}
 
-	if f.Document.InBatch() {
-		f.Document.Batch().Set(ref, m, firestore.MergeAll)
-		return nil
+	result, err := f.opts.updateResult(ctx, f.Document, op, []Update{{FieldPath: path, Value: ServerTimestamp}}, opts...)
+	if err != nil || result == nil {
+		return time.Time{}, err
 	}
 
-	_, err := ref.Set(ctx, m, firestore.MergeAll)
-	return err
+	return result.UpdateTime, nil
+}
+
+// Delete removes a specific field of type Timestamp from the document.
+//  err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").Delete(ctx)
+func (f *Timestamp) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "Timestamp.Delete", f.Document, f.Name, Delete, opts...)
 }
@@ -0,0 +1,196 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"cloud.google.com/go/firestore"
+	"github.com/remychantenay/fuego/document/internal"
+)
+
+// TypedArray represents a document field of type Array holding items of type T.
+//
+// T can be any type that Firestore can encode and decode: basic types, time.Time,
+// structs (incl. `firestore` struct tags) and maps (map[string]T).
+type TypedArray[T any] struct {
+
+	// Document is the underlying document (incl. ID and ref).
+	Document Document
+
+	// Name is the name of the field.
+	Name string
+
+	firestore *firestore.Client
+
+	opts *options
+}
+
+// ArrayOf returns a specific Array field of a document, holding items of type T.
+//  addresses := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses")
+func ArrayOf[T any](doc Document, name string) *TypedArray[T] {
+	f := Field[[]T](doc, name)
+	return &TypedArray[T]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
+}
+
+// field returns the typed field backing f.
+func (f *TypedArray[T]) field() *TypedField[[]T] {
+	return &TypedField[[]T]{
+		Document:  f.Document,
+		Name:      f.Name,
+		firestore: f.firestore,
+		opts:      f.opts,
+	}
+}
+
+// Retrieve returns the items of the field.
+//  addresses, err := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses").Retrieve(ctx)
+func (f *TypedArray[T]) Retrieve(ctx context.Context) ([]T, error) {
+	return f.field().get(ctx, "TypedArray.Retrieve")
+}
+
+// Override replaces the items of the field, the other fields of the document are left untouched.
+//  err := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses").Override(ctx, addresses)
+func (f *TypedArray[T]) Override(ctx context.Context, items []T, opts ...WriteOption) error {
+	return f.field().set(ctx, "TypedArray.Override", items, opts...)
+}
+
+// Append appends the provided items to the field.
+//
+// The update will be executed inside a transaction.
+// A NotFound error is returned if the field doesn't exist (see Array.Append).
+//  err := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses").Append(ctx, []Address{address})
+func (f *TypedArray[T]) Append(ctx context.Context, items []T) error {
+	return f.field().modify(ctx, "TypedArray.Append", func(current []T, exists bool) ([]T, bool, error) {
+		if !exists {
+			return nil, false, &firestore.FieldNotFoundError{Path: f.Name}
+		}
+		return internal.InsertAt(current, len(current), items...), true, nil
+	})
+}
+
+// Union adds the provided items which are not already part of the field, at its end (see Array.Union).
+//  err := document.ArrayOf[string](fuego.Document("users", "jsmith"), "Tags").Union(ctx, []string{"premium"})
+func (f *TypedArray[T]) Union(ctx context.Context, items []T, opts ...WriteOption) error {
+	return f.opts.set(ctx, "TypedArray.Union", f.Document, f.Name, firestore.ArrayUnion(values(items)...), opts...)
+}
+
+// Remove removes all the occurrences of the provided items from the field (see Array.Remove).
+//  err := document.ArrayOf[string](fuego.Document("users", "jsmith"), "Tags").Remove(ctx, []string{"beta"})
+func (f *TypedArray[T]) Remove(ctx context.Context, items []T, opts ...WriteOption) error {
+	return f.opts.set(ctx, "TypedArray.Remove", f.Document, f.Name, firestore.ArrayRemove(values(items)...), opts...)
+}
+
+// Push adds the provided items at the end of the field, keeping at most maxLen items (see Array.Push).
+//
+// The key given to DedupeBy (if any) is given items of type T.
+//  err := document.ArrayOf[string](fuego.Document("users", "jsmith"), "RecentlyViewed").Push(ctx, []string{productID}, 10)
+func (f *TypedArray[T]) Push(ctx context.Context, items []T, maxLen int, opts ...PushOption) error {
+	if maxLen < 1 {
+		op := operation{name: "TypedArray.Push", ref: f.Document.GetDocumentRef(), field: f.Name}
+		return f.opts.fail(ctx, op, fmt.Errorf("%w: %d", ErrInvalidLength, maxLen), op.attrs()...)
+	}
+
+	o := pushOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var key func(T) interface{}
+	if o.key != nil {
+		key = func(item T) interface{} { return o.key(item) }
+	}
+
+	return f.field().modify(ctx, "TypedArray.Push", func(current []T, exists bool) ([]T, bool, error) {
+		return internal.Push(current, items, maxLen, o.front, key), true, nil
+	})
+}
+
+// InsertAt inserts the provided items at a given index (0 to the length of the array) of the field (see Array.InsertAt).
+//  err := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses").InsertAt(ctx, 0, []Address{address})
+func (f *TypedArray[T]) InsertAt(ctx context.Context, index int, items []T) error {
+	return f.field().modify(ctx, "TypedArray.InsertAt", func(current []T, exists bool) ([]T, bool, error) {
+		if index < 0 || index > len(current) {
+			return nil, false, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, index, len(current))
+		}
+		return internal.InsertAt(current, index, items...), true, nil
+	})
+}
+
+// RemoveAt removes the item at a given index of the field (see Array.RemoveAt).
+//  err := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses").RemoveAt(ctx, 0)
+func (f *TypedArray[T]) RemoveAt(ctx context.Context, index int) error {
+	return f.field().modify(ctx, "TypedArray.RemoveAt", func(current []T, exists bool) ([]T, bool, error) {
+		if index < 0 || index >= len(current) {
+			return nil, false, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, index, len(current))
+		}
+		return internal.RemoveAt(current, index), true, nil
+	})
+}
+
+// RemoveWhere removes the items of the field matching a predicate and returns the number of items removed
+// (see Array.RemoveWhere).
+//  removed, err := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses").RemoveWhere(ctx, func(a Address) bool {
+//  	return a.City == "Dublin"
+//  })
+func (f *TypedArray[T]) RemoveWhere(ctx context.Context, fn func(item T) bool) (int, error) {
+	removed := 0
+	err := f.field().modify(ctx, "TypedArray.RemoveWhere", func(current []T, exists bool) ([]T, bool, error) {
+		var next []T
+		next, removed = internal.RemoveWhere(current, fn)
+		return next, removed > 0, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+// Contains returns true if the field contains the provided item (compared with reflect.DeepEqual),
+// false if the field doesn't exist.
+//  found, err := document.ArrayOf[string](fuego.Document("users", "jsmith"), "Tags").Contains(ctx, "premium")
+func (f *TypedArray[T]) Contains(ctx context.Context, item T) (bool, error) {
+	items, _, err := f.field().lookup(ctx, "TypedArray.Contains")
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range items {
+		if reflect.DeepEqual(v, item) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Len returns the number of items of the field, 0 if the field doesn't exist.
+//  count, err := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses").Len(ctx)
+func (f *TypedArray[T]) Len(ctx context.Context) (int, error) {
+	items, _, err := f.field().lookup(ctx, "TypedArray.Len")
+	if err != nil {
+		return 0, err
+	}
+
+	return len(items), nil
+}
+
+// Delete removes the field from the document.
+//  err := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses").Delete(ctx)
+func (f *TypedArray[T]) Delete(ctx context.Context, opts ...WriteOption) error {
+	return f.opts.set(ctx, "TypedArray.Delete", f.Document, f.Name, Delete, opts...)
+}
+
+// values returns the items as a slice of interface{}.
+func values[T any](items []T) []interface{} {
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		result[i] = item
+	}
+	return result
+}
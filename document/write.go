@@ -0,0 +1,177 @@
+package document
+
+import (
+	"reflect"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/remychantenay/fuego/document/internal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Update is a change applied to a field of a document (see Document.Update).
+//
+// Path is dot-separated (e.g. "Address.City"), FieldPath can be used instead for keys containing dots or backticks.
+type Update = firestore.Update
+
+var (
+	// Delete is an Update value removing the field from the document.
+	Delete = firestore.Delete
+
+	// ServerTimestamp is an Update value setting the field to the time at which the write is applied.
+	ServerTimestamp = firestore.ServerTimestamp
+)
+
+// fieldPath parses the path of a (possibly nested) field, nested fields being separated by dots
+// and keys possibly quoted with backticks (e.g. "Tokens.`web.app`").
+func fieldPath(field string) (firestore.FieldPath, error) {
+	return internal.ParsePath(field)
+}
+
+// updatePath returns the dot-separated path of an update.
+func updatePath(u Update) string {
+	if len(u.FieldPath) > 0 {
+		return internal.JoinPath(u.FieldPath...)
+	}
+	return u.Path
+}
+
+// idempotent returns true if applying updates more than once has the same effect as applying them once,
+// which isn't the case of the transforms (e.g. firestore.Increment) at the exception of ServerTimestamp.
+func idempotent(updates []Update) bool {
+	const pkg = "cloud.google.com/go/firestore"
+	for _, u := range updates {
+		if u.Value == Delete || u.Value == ServerTimestamp {
+			continue
+		}
+		if t := reflect.TypeOf(u.Value); t != nil && t.PkgPath() == pkg {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteOption configures a write (e.g. a precondition).
+type WriteOption func(*writeOptions)
+
+// IfExists only applies the write if the document exists.
+//
+// A Conflict error wrapping ErrPreconditionFailed is returned otherwise.
+//  err := fuego.Document("users", "jsmith").String("FirstName").Update(ctx, "Jane", document.IfExists)
+var IfExists WriteOption = func(o *writeOptions) {
+	o.preconds = append(o.preconds, precondition{exists: true})
+}
+
+// IfUpdatedAt only applies the write if the document has not been updated since t
+// (i.e. its update time, see RetrieveWithMeta, is t).
+//
+// A Conflict error wrapping ErrPreconditionFailed is returned otherwise.
+//  meta, err := doc.RetrieveWithMeta(ctx, &user)
+//  // ...
+//  err = doc.Replace(ctx, user, document.IfUpdatedAt(meta.UpdateTime))
+func IfUpdatedAt(t time.Time) WriteOption {
+	return func(o *writeOptions) {
+		o.preconds = append(o.preconds, precondition{updateTime: t})
+	}
+}
+
+// Fields restricts Document.Merge to the given fields (e.g. "Address.City", keys can be quoted with backticks).
+// An InvalidArgument error is returned if one of them isn't a valid path.
+//  err := fuego.Document("users", "jsmith").Merge(ctx, user, document.Fields("FirstName", "LastName"))
+func Fields(fields ...string) WriteOption {
+	return func(o *writeOptions) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+// writeOptions holds the settings of a write.
+type writeOptions struct {
+	preconds []precondition
+	fields   []string
+}
+
+// newWriteOptions creates and returns writeOptions with the provided WriteOption(s) applied.
+func newWriteOptions(opts ...WriteOption) writeOptions {
+	o := writeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// conditional returns true if the write has preconditions.
+func (o writeOptions) conditional() bool {
+	return len(o.preconds) > 0
+}
+
+// update returns the Firestore precondition of an update applying the preconditions of the write.
+//
+// Firestore accepts a single precondition per update, and an update already requires the document to exist:
+// only an update time precondition is kept.
+func (o writeOptions) update() []firestore.Precondition {
+	for _, p := range o.preconds {
+		if !p.exists {
+			return []firestore.Precondition{p.firestore()}
+		}
+	}
+	return nil
+}
+
+// deletion returns the Firestore precondition of a deletion applying the preconditions of the write
+// (and requiring the document to exist if mustExist is true).
+//
+// Firestore accepts a single precondition per deletion: an update time precondition implies that the document exists.
+func (o writeOptions) deletion(mustExist bool) []firestore.Precondition {
+	if preconds := o.update(); len(preconds) > 0 {
+		return preconds
+	}
+
+	if mustExist || o.conditional() {
+		return []firestore.Precondition{firestore.Exists}
+	}
+	return nil
+}
+
+// check returns ErrPreconditionFailed if a document snapshot doesn't meet the preconditions of the write.
+//
+// Firestore only supports preconditions on updates and deletions, they are checked this way for the other writes.
+func (o writeOptions) check(s *firestore.DocumentSnapshot) error {
+	for _, p := range o.preconds {
+		if !p.met(s) {
+			return ErrPreconditionFailed
+		}
+	}
+	return nil
+}
+
+// precondition is a condition a document has to meet for a write to be applied.
+type precondition struct {
+	exists     bool
+	updateTime time.Time
+}
+
+// firestore returns the equivalent Firestore precondition.
+func (p precondition) firestore() firestore.Precondition {
+	if p.exists {
+		return firestore.Exists
+	}
+	return firestore.LastUpdateTime(p.updateTime)
+}
+
+// met returns true if a document snapshot (possibly of a document that doesn't exist) meets the precondition.
+func (p precondition) met(s *firestore.DocumentSnapshot) bool {
+	if s == nil || !s.Exists() {
+		return false
+	}
+	return p.exists || s.UpdateTime.Equal(p.updateTime)
+}
+
+// checked gets a document within a transaction and checks the preconditions of a write.
+func (o writeOptions) checked(tx *firestore.Transaction, ref *firestore.DocumentRef) error {
+	s, err := tx.Get(ref)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return err
+	}
+	return o.check(s)
+}
@@ -1,8 +1,77 @@
 package fuego
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/remychantenay/fuego/internal/errs"
+)
 
 var (
 	// ErrBatchWriteNotStarted indicates that the a commit can't happen if the batch write hasn't been started.
 	ErrBatchWriteNotStarted = errors.New("fuego: no batch write started")
 )
+
+// Error is the error returned by the fuego operations (incl. the document and collection packages).
+// It carries the operation, the document path, the field name (if any) and the kind of the error.
+//
+// Use errors.As to access its details and errors.Is to match it against the sentinel error of its kind
+// (e.g. ErrNotFound) or against the error it wraps (e.g. document.ErrDocumentNotExist).
+//  var fe *fuego.Error
+//  if errors.As(err, &fe) {
+//  	fmt.Println(fe.Op, fe.Path, fe.Field, fe.Kind)
+//  }
+type Error = errs.Error
+
+// ErrorKind classifies an Error.
+type ErrorKind = errs.Kind
+
+const (
+	// Unknown is the kind of the errors that couldn't be classified.
+	Unknown = errs.Unknown
+
+	// NotFound indicates that a document (or field) doesn't exist.
+	NotFound = errs.NotFound
+
+	// AlreadyExists indicates that a document already exists.
+	AlreadyExists = errs.AlreadyExists
+
+	// Conflict indicates that a precondition failed or that a transaction has been aborted because of contention.
+	//
+	// Its gRPC code is FailedPrecondition (i.e. not worth retrying), unless it wraps a gRPC error (e.g. Aborted).
+	Conflict = errs.Conflict
+
+	// PermissionDenied indicates that the caller is not allowed to perform the operation.
+	PermissionDenied = errs.PermissionDenied
+
+	// Unavailable indicates a transient failure (e.g. the service is unavailable, a quota is exhausted, a deadline expired).
+	Unavailable = errs.Unavailable
+
+	// InvalidArgument indicates that the operation is invalid (e.g. an invalid field path or value).
+	InvalidArgument = errs.InvalidArgument
+
+	// TypeMismatch indicates that a value doesn't have the expected type.
+	TypeMismatch = errs.TypeMismatch
+)
+
+var (
+	// ErrNotFound is matched (with errors.Is) by the errors of kind NotFound.
+	ErrNotFound = errs.ErrNotFound
+
+	// ErrAlreadyExists is matched (with errors.Is) by the errors of kind AlreadyExists.
+	ErrAlreadyExists = errs.ErrAlreadyExists
+
+	// ErrConflict is matched (with errors.Is) by the errors of kind Conflict.
+	ErrConflict = errs.ErrConflict
+
+	// ErrPermissionDenied is matched (with errors.Is) by the errors of kind PermissionDenied.
+	ErrPermissionDenied = errs.ErrPermissionDenied
+
+	// ErrUnavailable is matched (with errors.Is) by the errors of kind Unavailable.
+	ErrUnavailable = errs.ErrUnavailable
+
+	// ErrInvalidArgument is matched (with errors.Is) by the errors of kind InvalidArgument.
+	ErrInvalidArgument = errs.ErrInvalidArgument
+
+	// ErrTypeMismatch is matched (with errors.Is) by the errors of kind TypeMismatch.
+	ErrTypeMismatch = errs.ErrTypeMismatch
+)
@@ -2,11 +2,19 @@ package fuego
 
 import (
 	"context"
+	"log/slog"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/remychantenay/fuego/collection"
+	"github.com/remychantenay/fuego/counter"
 	"github.com/remychantenay/fuego/document"
+	"github.com/remychantenay/fuego/internal/errs"
+	"github.com/remychantenay/fuego/internal/location"
+	"github.com/remychantenay/fuego/internal/logging"
+	"github.com/remychantenay/fuego/internal/ratelimit"
+	"github.com/remychantenay/fuego/internal/retry"
 )
 
 // Fuego is a wrapper for the Firestore client.
@@ -19,13 +27,39 @@ type Fuego struct {
 	// WriteBatch is a ptr to a writebatch.
 	// will be nil if not started with StartBatch() or cancelled with CancelBatch().
 	WriteBatch *firestore.WriteBatch
+
+	logger *logging.Logger
+
+	retry *retry.Policies
+
+	limits *ratelimit.Registry
+
+	conversions document.Conversion
+
+	ids IDStrategy
+
+	locations *location.Cache
+
+	// transaction will be nil outside of RunTransaction().
+	transaction *firestore.Transaction
 }
 
 // New creates and returns a Fuego wrapper.
-func New(fs *firestore.Client) *Fuego {
+func New(fs *firestore.Client, opts ...Option) *Fuego {
+	c := config{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
 	return &Fuego{
 		FirestoreClient: fs,
 		WriteBatch:      nil,
+		logger:          logging.New(c.logger, c.redact),
+		retry:           c.retries,
+		limits:          c.limits,
+		conversions:     c.conversions,
+		ids:             c.ids,
+		locations:       location.NewCache(),
 	}
 }
 
@@ -36,11 +70,33 @@ func (f *Fuego) StartBatch() {
 }
 
 // CommitBatch commits the write batch previously started with StartBatch().
+//
+// As the batch may hold non-idempotent writes, the commit is only retried
+// when it is known not to have been applied.
 func (f *Fuego) CommitBatch(ctx context.Context) ([]*firestore.WriteResult, error) {
+	const op = "Fuego.CommitBatch"
 	if f.WriteBatch == nil {
-		return nil, ErrBatchWriteNotStarted
+		err := errs.New(op, "", "", errs.InvalidArgument, ErrBatchWriteNotStarted)
+		f.logger.Failure(ctx, op, "", err)
+		return nil, err
 	}
-	return f.WriteBatch.Commit(ctx)
+
+	var wr []*firestore.WriteResult
+	err := f.retry.For(op).Do(ctx, retry.NonIdempotent, func(ctx context.Context) error {
+		var err error
+		wr, err = f.WriteBatch.Commit(ctx)
+		return err
+	}, func(attempt int, wait time.Duration, err error) {
+		f.logger.Retry(ctx, op, "", attempt, wait, err)
+	})
+	if err != nil {
+		err = errs.Wrap(op, "", "", err)
+		f.logger.Failure(ctx, op, "", err)
+		return nil, err
+	}
+
+	f.logger.BatchCommit(ctx, op, "", slog.Int("writes", len(wr)))
+	return wr, nil
 }
 
 // CancelBatch cancels an on-going write batch (if any).
@@ -48,19 +104,106 @@ func (f *Fuego) CancelBatch() {
 	f.WriteBatch = nil
 }
 
+// RunTransaction runs fn inside a transaction.
+//
+// The documents (and their fields) obtained from the Fuego passed to fn are part of the transaction:
+// reads are performed within the transaction and writes are added to it.
+// As required by Firestore, all the reads have to be performed before the writes.
+//
+// fn may be called more than once when the transaction is retried because of contention.
+//  err := fuegoClient.RunTransaction(ctx, func(ctx context.Context, tx *fuego.Fuego) error {
+//  	if _, err := tx.Document("users", "jsmith").Create(ctx, user); err != nil {
+//  		return err
+//  	}
+//  	return tx.Document("stats", "users").Number("Count").Increment(ctx)
+//  })
+func (f *Fuego) RunTransaction(ctx context.Context, fn func(ctx context.Context, tx *Fuego) error) error {
+	const op = "Fuego.RunTransaction"
+	f.logger.Operation(ctx, op, "")
+
+	attempt := 0
+	err := f.retry.For(op).Do(ctx, retry.NonIdempotent, func(ctx context.Context) error {
+		return f.FirestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			attempt++
+			if attempt > 1 {
+				f.logger.TransactionRetry(ctx, op, "", attempt)
+			}
+
+			t := *f
+			t.WriteBatch = nil
+			t.transaction = tx
+			return fn(ctx, &t)
+		})
+	}, func(attempt int, wait time.Duration, err error) {
+		f.logger.Retry(ctx, op, "", attempt, wait, err)
+	})
+	if err != nil {
+		err = errs.Wrap(op, "", "", err)
+		f.logger.Failure(ctx, op, "", err)
+		return err
+	}
+
+	return nil
+}
+
 // Document returns a new FirestoreDocument.
 func (f *Fuego) Document(path, documentID string) *document.FirestoreDocument {
-	return document.New(f.FirestoreClient, cleanPath(path), documentID, f.WriteBatch)
+	path = cleanPath(path)
+	return document.New(f.FirestoreClient, path, documentID, f.WriteBatch,
+		document.WithLogger(f.logger.Slog()),
+		document.WithLogRedaction(f.logger.Redactor()),
+		document.WithRetryPolicies(f.retry),
+		document.WithLimiter(f.limits.For(path)),
+		document.WithConversions(f.conversions),
+		document.WithIDStrategy(f.ids),
+		document.WithTransaction(f.transaction),
+		document.WithLocations(f.locations),
+	)
 }
 
-// DocumentWithGeneratedID returns a new FirestoreDocument without ID.
+// DocumentWithGeneratedID returns a new FirestoreDocument which ID is generated (see WithIDStrategy).
+//
+// The ID is generated once and kept, it is returned by Create and Upsert.
 func (f *Fuego) DocumentWithGeneratedID(path string) *document.FirestoreDocument {
 	return f.Document(path, "")
 }
 
 // Collection returns a new FirestoreCollection.
 func (f *Fuego) Collection(path string) *collection.FirestoreCollection {
-	return collection.New(f.FirestoreClient, cleanPath(path))
+	path = cleanPath(path)
+	return collection.New(f.FirestoreClient, path,
+		collection.WithLogger(f.logger.Slog()),
+		collection.WithLogRedaction(f.logger.Redactor()),
+		collection.WithRetryPolicies(f.retry),
+		collection.WithLimiter(f.limits.For(path)),
+		collection.WithBulkLimiter(f.limits.Bulk(path)),
+	)
+}
+
+// Counter returns a new FirestoreCounter, spread over the given number of shards (1 to counter.MaxShards).
+//
+// The operations of a counter are always applied directly: they are not part of the transaction
+// or write batch (if any) of the client.
+//  err := fuegoClient.Counter("posts", "123", 10).Increment(ctx, 1)
+func (f *Fuego) Counter(path, documentID string, shards int) *counter.FirestoreCounter {
+	path = cleanPath(path)
+	return counter.New(f.FirestoreClient, path, documentID, shards,
+		counter.WithLogger(f.logger.Slog()),
+		counter.WithLogRedaction(f.logger.Redactor()),
+		counter.WithRetryPolicies(f.retry.For),
+		counter.WithLimiter(f.limits.For(path)),
+	)
+}
+
+// Location returns the location with the given name from the IANA Time Zone database (e.g. "America/Los_Angeles"),
+// the locations being cached by the client.
+//  loc, err := fuegoClient.Location("America/Los_Angeles")
+func (f *Fuego) Location(name string) (*time.Location, error) {
+	loc, err := f.locations.Load(name)
+	if err != nil {
+		return nil, errs.New("Fuego.Location", "", "", errs.InvalidArgument, err)
+	}
+	return loc, nil
 }
 
 // cleanPath cleans and returns a given path.
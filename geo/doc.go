@@ -0,0 +1,28 @@
+/*
+Package geo provides locations which can be queried by distance.
+
+Firestore can't query geopoints by distance: a location is stored in a field as a Point, i.e. the geopoint
+and its geohash (https://en.wikipedia.org/wiki/Geohash). The documents within a radius are queried with
+a few range queries on the geohashes (the ones covering the circle), then filtered by their precise distance.
+
+Usage
+
+Storing a location:
+
+	err := document.Field[geo.Point](fuego.Document("shops", "123"), "Location").Set(ctx, geo.NewPoint(48.8584, 2.2945))
+
+	// or as part of a struct...
+	type Shop struct {
+		Name     string    `firestore:"Name"`
+		Location geo.Point `firestore:"Location"`
+	}
+
+Retrieving the documents within 1km, nearest first:
+
+	center := &latlng.LatLng{Latitude: 48.8566, Longitude: 2.3522}
+	shops, err := fuego.Collection("shops").WithinRadius(ctx, &Shop{}, "Location", center, 1000)
+
+	// Note the required type assertion
+	fmt.Println("Nearest: ", shops[0].(*Shop).Name)
+*/
+package geo
@@ -0,0 +1,13 @@
+package geo
+
+import "errors"
+
+// The errors below are wrapped by the errors of the queries by distance (when relevant),
+// they can be matched with errors.Is.
+var (
+	// ErrInvalidLocation indicates that a location is missing or out of range (latitude -90 to 90, longitude -180 to 180).
+	ErrInvalidLocation = errors.New("geo: invalid location")
+
+	// ErrInvalidRadius indicates that a radius is not strictly positive.
+	ErrInvalidRadius = errors.New("geo: invalid radius")
+)
@@ -0,0 +1,82 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/remychantenay/fuego/geo/internal"
+	"google.golang.org/genproto/googleapis/type/latlng"
+)
+
+const (
+	// GeoPointField is the name of the key holding the geopoint of a Point.
+	GeoPointField = "GeoPoint"
+
+	// GeohashField is the name of the key holding the geohash of a Point.
+	GeohashField = "Geohash"
+
+	// Precision is the number of characters of the geohashes (about 1m x 0.6m).
+	Precision = 10
+)
+
+// Point is a location as stored in a document field (i.e. a map), with the geohash needed to query it by distance.
+type Point struct {
+
+	// GeoPoint is the location.
+	GeoPoint *latlng.LatLng `firestore:"GeoPoint"`
+
+	// Geohash is the geohash of the location.
+	Geohash string `firestore:"Geohash"`
+}
+
+// NewPoint returns the Point of the given location.
+//  point := geo.NewPoint(48.8584, 2.2945)
+func NewPoint(lat, lng float64) Point {
+	return Point{
+		GeoPoint: &latlng.LatLng{Latitude: lat, Longitude: lng},
+		Geohash:  Hash(lat, lng),
+	}
+}
+
+// Hash returns the geohash of a location, as stored in a Point.
+func Hash(lat, lng float64) string {
+	return internal.Encode(lat, lng, Precision)
+}
+
+// Distance returns the distance between two locations, in meters.
+func Distance(a, b *latlng.LatLng) float64 {
+	return internal.Distance(a.GetLatitude(), a.GetLongitude(), b.GetLatitude(), b.GetLongitude())
+}
+
+// Range is a range of geohashes, Start and End included.
+type Range struct {
+	Start string
+	End   string
+}
+
+// QueryRanges returns the ranges of geohashes covering the circle of the given center and radius (in meters).
+//
+// The ranges cover more than the circle: the locations they contain have to be filtered by distance.
+// They are never more precise than the geohashes (see Precision), however small the radius.
+func QueryRanges(center *latlng.LatLng, radius float64) []Range {
+	ranges := internal.QueryRanges(center.GetLatitude(), center.GetLongitude(), radius, Precision)
+
+	result := make([]Range, len(ranges))
+	for i, r := range ranges {
+		result[i] = Range{Start: r.Start, End: r.End}
+	}
+	return result
+}
+
+// Validate returns ErrInvalidLocation if a location is nil or out of range.
+func Validate(location *latlng.LatLng) error {
+	if location == nil {
+		return fmt.Errorf("%w: nil", ErrInvalidLocation)
+	}
+
+	lat, lng := location.GetLatitude(), location.GetLongitude()
+	if math.IsNaN(lat) || math.IsNaN(lng) || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return fmt.Errorf("%w: (%g, %g)", ErrInvalidLocation, lat, lng)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package internal
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	// base32 is the alphabet of the geohashes.
+	base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+	// bitsPerChar is the number of bits encoded by each character of a geohash.
+	bitsPerChar = 5
+
+	// maxPrecision is the max. number of characters of a geohash.
+	maxPrecision = 22
+
+	// maxBits is the max. number of bits of a geohash.
+	maxBits = maxPrecision * bitsPerChar
+
+	// earthRadius is the mean radius of the Earth, in meters.
+	earthRadius = 6371008.8
+
+	// earthMeridionalCircumference is the circumference of the Earth along a meridian, in meters.
+	earthMeridionalCircumference = 40007860
+
+	// earthEquatorialRadius is the radius of the Earth at the equator, in meters.
+	earthEquatorialRadius = 6378137.0
+
+	// metersPerDegreeLatitude is the length of a degree of latitude, in meters.
+	metersPerDegreeLatitude = 110574
+
+	// e2 is the square of the eccentricity of the Earth's ellipsoid (WGS 84).
+	e2 = 0.00669447819799
+
+	epsilon = 1e-12
+)
+
+// Range is a range of geohashes, from Start to End (both inclusive, when used as a query).
+type Range struct {
+	Start string
+	End   string
+}
+
+// Encode returns the geohash of a location, with the given number of characters (1 to 22).
+func Encode(lat, lng float64, precision int) string {
+	precision = max(1, min(precision, maxPrecision))
+
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+
+	var b strings.Builder
+	value, bits, even := 0, 0, true
+	for b.Len() < precision {
+		r, v := &latRange, lat
+		if even {
+			r, v = &lngRange, lng
+		}
+
+		mid := (r[0] + r[1]) / 2
+		value <<= 1
+		if v >= mid {
+			value |= 1
+			r[0] = mid
+		} else {
+			r[1] = mid
+		}
+		even = !even
+
+		bits++
+		if bits == bitsPerChar {
+			b.WriteByte(base32[value])
+			value, bits = 0, 0
+		}
+	}
+	return b.String()
+}
+
+// Distance returns the distance between two locations, in meters (haversine formula).
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return earthRadius * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// QueryRanges returns the ranges of geohashes covering a circle (center and radius in meters),
+// for geohashes of the given precision (i.e. number of characters).
+//
+// The ranges cover more than the circle: the locations they match have to be filtered by distance.
+// They are never more precise than the geohashes, however small the radius.
+func QueryRanges(lat, lng, radius float64, precision int) []Range {
+	bits := max(1, min(boundingBoxBits(lat, lng, radius), precision*bitsPerChar))
+	precision = int(math.Ceil(float64(bits) / bitsPerChar))
+
+	var ranges []Range
+	seen := make(map[Range]bool)
+	for _, c := range boundingBoxCoordinates(lat, lng, radius) {
+		r := query(Encode(c[0], c[1], precision), bits)
+		if !seen[r] {
+			seen[r] = true
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// query returns the range of the geohashes starting with the first bits of a geohash.
+func query(geohash string, bits int) Range {
+	precision := int(math.Ceil(float64(bits) / bitsPerChar))
+	if len(geohash) < precision {
+		return Range{Start: geohash, End: geohash + "~"}
+	}
+
+	geohash = geohash[:precision]
+	base := geohash[:len(geohash)-1]
+	last := strings.IndexByte(base32, geohash[len(geohash)-1])
+	significant := bits - len(base)*bitsPerChar
+	unused := bitsPerChar - significant
+
+	start := (last >> unused) << unused
+	end := start + (1 << unused)
+	if end > len(base32)-1 {
+		return Range{Start: base + string(base32[start]), End: base + "~"}
+	}
+	return Range{Start: base + string(base32[start]), End: base + string(base32[end])}
+}
+
+// boundingBoxBits returns the number of bits of the geohashes needed to cover a square of the given size (in meters).
+func boundingBoxBits(lat, lng, size float64) int {
+	latDelta := size / metersPerDegreeLatitude
+	north := math.Min(90, lat+latDelta)
+	south := math.Max(-90, lat-latDelta)
+
+	bitsLat := int(math.Floor(latitudeBits(size))) * 2
+	bitsLngNorth := int(math.Floor(longitudeBits(size, north)))*2 - 1
+	bitsLngSouth := int(math.Floor(longitudeBits(size, south)))*2 - 1
+	return min(bitsLat, bitsLngNorth, bitsLngSouth, maxBits)
+}
+
+// boundingBoxCoordinates returns the center, corners and edge midpoints of the box bounding a circle.
+func boundingBoxCoordinates(lat, lng, radius float64) [][2]float64 {
+	latDegrees := radius / metersPerDegreeLatitude
+	north := math.Min(90, lat+latDegrees)
+	south := math.Max(-90, lat-latDegrees)
+	lngDegrees := math.Max(metersToLongitudeDegrees(radius, north), metersToLongitudeDegrees(radius, south))
+
+	var coordinates [][2]float64
+	for _, la := range []float64{lat, north, south} {
+		coordinates = append(coordinates,
+			[2]float64{la, lng},
+			[2]float64{la, wrapLongitude(lng - lngDegrees)},
+			[2]float64{la, wrapLongitude(lng + lngDegrees)},
+		)
+	}
+	return coordinates
+}
+
+// metersToLongitudeDegrees returns the number of degrees of longitude a distance covers at a given latitude.
+func metersToLongitudeDegrees(distance, lat float64) float64 {
+	r := radians(lat)
+	num := math.Cos(r) * earthEquatorialRadius * math.Pi / 180
+	denom := 1 / math.Sqrt(1-e2*math.Sin(r)*math.Sin(r))
+	delta := num * denom
+	if delta < epsilon {
+		if distance > 0 {
+			return 360
+		}
+		return 0
+	}
+	return math.Min(360, distance/delta)
+}
+
+// latitudeBits returns the number of bits of latitude needed for a given resolution (in meters).
+func latitudeBits(resolution float64) float64 {
+	return math.Min(math.Log2(earthMeridionalCircumference/2/resolution), maxBits)
+}
+
+// longitudeBits returns the number of bits of longitude needed for a given resolution (in meters) at a given latitude.
+func longitudeBits(resolution, lat float64) float64 {
+	degrees := metersToLongitudeDegrees(resolution, lat)
+	if math.Abs(degrees) > 0.000001 {
+		return math.Max(1, math.Log2(360/degrees))
+	}
+	return 1
+}
+
+// wrapLongitude wraps a longitude to [-180, 180].
+func wrapLongitude(lng float64) float64 {
+	if lng >= -180 && lng <= 180 {
+		return lng
+	}
+
+	adjusted := lng + 180
+	if adjusted > 0 {
+		return math.Mod(adjusted, 360) - 180
+	}
+	return 180 - math.Mod(-adjusted, 360)
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
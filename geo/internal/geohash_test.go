@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+
+	tests := []struct {
+		lat, lng  float64
+		precision int
+		want      string
+	}{
+		{lat: 57.64911, lng: 10.40744, precision: 11, want: "u4pruydqqvj"},
+		{lat: 48.8584, lng: 2.2945, precision: 9, want: "u09tunquc"},
+		{lat: -25.382708, lng: -49.265506, precision: 7, want: "6gkzwgj"},
+		{lat: 0, lng: 0, precision: 1, want: "s"},
+		{lat: -90, lng: -180, precision: 5, want: "00000"},
+		{lat: 90, lng: 180, precision: 5, want: "zzzzz"},
+	}
+
+	for _, test := range tests {
+		result := Encode(test.lat, test.lng, test.precision)
+
+		if result != test.want {
+			t.Fatalf("(%f, %f) -> Got %s but expected %s", test.lat, test.lng, result, test.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// Paris to London
+	result := Distance(48.8566, 2.3522, 51.5074, -0.1278)
+
+	if math.Abs(result-343_500) > 1_000 {
+		t.Fatalf("Got %f but expected about %d meters", result, 343_500)
+	}
+
+	if Distance(48.8566, 2.3522, 48.8566, 2.3522) != 0 {
+		t.Fatal("The distance between a location and itself is expected to be 0")
+	}
+}
+
+func TestQueryRanges(t *testing.T) {
+	lat, lng, radius := 48.8584, 2.2945, 1_000.0
+
+	ranges := QueryRanges(lat, lng, radius, maxPrecision)
+	if len(ranges) == 0 || len(ranges) > 9 {
+		t.Fatalf("Expected 1 to 9 ranges, got %d", len(ranges))
+	}
+
+	// The locations within the radius are expected to be covered by a range
+	for _, offset := range [][2]float64{{0, 0}, {0.008, 0}, {-0.008, 0}, {0, 0.012}, {0, -0.012}, {0.006, 0.008}} {
+		la, ln := lat+offset[0], lng+offset[1]
+		if Distance(lat, lng, la, ln) > radius {
+			t.Fatalf("(%f, %f) is expected to be within the radius", la, ln)
+		}
+
+		hash := Encode(la, ln, maxPrecision)
+		covered := false
+		for _, r := range ranges {
+			if hash >= r.Start && hash <= r.End {
+				covered = true
+			}
+		}
+
+		if !covered {
+			t.Fatalf("(%f, %f) -> %s isn't covered by %v", la, ln, hash, ranges)
+		}
+	}
+}
+
+func TestQueryRanges_TinyRadius(t *testing.T) {
+	lat, lng, radius, precision := 48.8584, 2.2945, 0.01, 10
+
+	ranges := QueryRanges(lat, lng, radius, precision)
+	if len(ranges) == 0 {
+		t.Fatal("Expected at least 1 range")
+	}
+
+	for _, r := range ranges {
+		if len(r.Start) > precision || len(r.End) > precision {
+			t.Fatalf("%v is more precise than the geohashes", r)
+		}
+	}
+
+	// The geohash of the center is expected to be covered by a range
+	hash := Encode(lat, lng, precision)
+	covered := false
+	for _, r := range ranges {
+		if hash >= r.Start && hash <= r.End {
+			covered = true
+		}
+	}
+
+	if !covered {
+		t.Fatalf("%s isn't covered by %v", hash, ranges)
+	}
+}
+
+func TestWrapLongitude(t *testing.T) {
+
+	tests := []struct {
+		with, want float64
+	}{
+		{with: 0, want: 0},
+		{with: 180, want: 180},
+		{with: 190, want: -170},
+		{with: -190, want: 170},
+		{with: 540, want: -180},
+	}
+
+	for _, test := range tests {
+		if result := wrapLongitude(test.with); math.Abs(result-test.want) > 1e-9 {
+			t.Fatalf("%f -> Got %f but expected %f", test.with, result, test.want)
+		}
+	}
+}
@@ -2,12 +2,22 @@ package fuego
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	firebase "firebase.google.com/go"
+	"github.com/remychantenay/fuego/collection"
+	"github.com/remychantenay/fuego/document"
+	"github.com/remychantenay/fuego/geo"
+	"google.golang.org/genproto/googleapis/type/latlng"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var fuego *Fuego
@@ -94,10 +104,198 @@ func TestIntegration_Document_Create(t *testing.T) {
 		Premium:    false,
 	}
 
-	err := fuego.Document("users", "jsmith").Create(ctx, user)
+	_, err := fuego.Document("users", "jsmith").Create(ctx, user)
 	if err != nil {
 		t.Fatalf(err.Error())
 	}
+
+	// Making sure the document isn't overwritten
+	_, err = fuego.Document("users", "jsmith").Create(ctx, user)
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("The error is expected to be an AlreadyExists error, got %v.", err)
+	}
+}
+
+func TestIntegration_Document_Replace_NotFound(t *testing.T) {
+	ctx := context.Background()
+
+	err := fuego.Document("users", "nobody").Replace(ctx, TestedStruct{FirstName: "Nobody"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("The error is expected to be a NotFound error, got %v.", err)
+	}
+
+	// The existence of the document is checked before the given preconditions
+	err = fuego.Document("users", "nobody").Replace(ctx, TestedStruct{FirstName: "Nobody"}, document.IfUpdatedAt(time.Now()))
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, document.ErrDocumentNotExist) {
+		t.Fatalf("The error is expected to be a NotFound error, got %v.", err)
+	}
+}
+
+func TestIntegration_Document_Replace_Batch(t *testing.T) {
+	ctx := context.Background()
+
+	client := New(fuego.FirestoreClient)
+	client.StartBatch()
+
+	if err := client.Document("users", "batched").Replace(ctx, TestedStruct{FirstName: "Batched"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The document doesn't exist: the whole batch fails
+	if _, err := client.CommitBatch(ctx); err == nil {
+		t.Fatal("The batch is expected to fail.")
+	}
+
+	if fuego.Document("users", "batched").Exists(ctx) {
+		t.Fatal("The document is not expected to be created.")
+	}
+}
+
+func TestIntegration_Document_IfUpdatedAt(t *testing.T) {
+	ctx := context.Background()
+
+	doc := fuego.Document("users", "jsmith")
+	user := TestedStruct{}
+	meta, err := doc.RetrieveWithMeta(ctx, &user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if meta.UpdateTime.IsZero() || meta.CreateTime.After(meta.UpdateTime) {
+		t.Fatalf("Unexpected metadata: %+v.", meta)
+	}
+
+	err = doc.String("LastName").Update(ctx, "Smith", document.IfUpdatedAt(meta.UpdateTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Updates already require the document to exist
+	if err := doc.String("LastName").Update(ctx, "Smith", document.IfExists); err != nil {
+		t.Fatal(err)
+	}
+
+	// The document has been updated since the retrieval: a stale update time never succeeds, it isn't worth retrying
+	err = doc.Replace(ctx, user, document.IfUpdatedAt(meta.UpdateTime))
+	if !errors.Is(err, ErrConflict) || !errors.Is(err, document.ErrPreconditionFailed) || status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("The error is expected to be a Conflict error, got %v (%s).", err, status.Code(err))
+	}
+
+	// The precondition is checked by Firestore, which code is kept
+	err = doc.String("LastName").Update(ctx, "Smith", document.IfUpdatedAt(meta.UpdateTime))
+	if !errors.Is(err, ErrConflict) || !errors.Is(err, document.ErrPreconditionFailed) || status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("The error is expected to be a Conflict error, got %v (%s).", err, status.Code(err))
+	}
+}
+
+func TestIntegration_Document_Merge_Fields(t *testing.T) {
+	ctx := context.Background()
+
+	doc := fuego.Document("users", "jsmith")
+	defer doc.Update(ctx, Update{Path: "Tokens", Value: Delete})
+
+	// Keys containing dots are quoted with backticks, as for the other field paths
+	err := doc.Merge(ctx, map[string]interface{}{
+		"Tokens": map[string]interface{}{"web.app": "WEB_123", "Android": "AND_123"},
+	}, document.Fields("Tokens.`web.app`"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := doc.Map("Tokens").Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := tokens["Android"]; ok || tokens["web.app"] != "WEB_123" {
+		t.Fatalf("Only the given key is expected to be merged, got %v", tokens)
+	}
+
+	err = doc.Merge(ctx, map[string]interface{}{}, document.Fields("Tokens..Android"))
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("The error is expected to be an InvalidArgument error, got %v.", err)
+	}
+}
+
+func TestIntegration_Document_Update(t *testing.T) {
+	ctx := context.Background()
+
+	doc := fuego.Document("users", "jsmith")
+	err := doc.Update(ctx,
+		Update{Path: "Settings.Theme", Value: "dark"},
+		Update{Path: "Settings.UpdatedAt", Value: ServerTimestamp},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	theme, err := doc.String("Settings.Theme").Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if theme != "dark" {
+		t.Fatalf("The nested field is expected to be dark, got %s.", theme)
+	}
+
+	if err := doc.Update(ctx, Update{Path: "Settings", Value: Delete}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Making sure missing documents aren't created
+	err = fuego.Document("users", "nobody").String("FirstName").Update(ctx, "Nobody")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("The error is expected to be a NotFound error, got %v.", err)
+	}
+}
+
+func TestIntegration_RunTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	err := fuego.RunTransaction(ctx, func(ctx context.Context, tx *Fuego) error {
+		age, err := tx.Document("users", "jsmith").Number("Age").Retrieve(ctx)
+		if err != nil {
+			return err
+		}
+
+		return tx.Document("users", "jsmith").Merge(ctx, map[string]interface{}{
+			"Age":     age,
+			"Premium": true,
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	premium, err := fuego.Document("users", "jsmith").Boolean("Premium").Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !premium {
+		t.Fatal("The document is expected to be updated by the transaction.")
+	}
+}
+
+func TestIntegration_RunTransaction_Conflict(t *testing.T) {
+	ctx := context.Background()
+
+	client := New(fuego.FirestoreClient, WithRetryPolicy(DefaultRetryPolicy))
+	transitions := document.Transitions{"": {"started"}}
+
+	// A rejected transition is deterministic: neither Firestore nor the retry policy run the transaction again
+	calls := 0
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *Fuego) error {
+		calls++
+		return tx.Document("users", "jsmith").State("Onboarding", transitions).Transition(ctx, "completed")
+	})
+	if !errors.Is(err, ErrConflict) || !errors.Is(err, document.ErrIllegalTransition) || status.Code(err) == codes.Aborted {
+		t.Fatalf("The error is expected to be a Conflict error matching ErrIllegalTransition, got %v (%s).", err, status.Code(err))
+	}
+
+	if calls != 1 {
+		t.Fatalf("The transaction is expected to run once, ran %d times.", calls)
+	}
 }
 
 func TestIntegration_Document_Exists(t *testing.T) {
@@ -121,6 +319,21 @@ func TestIntegration_Document_Retrieve(t *testing.T) {
 	fmt.Println("FirstName: ", user.FirstName)
 }
 
+func TestIntegration_Document_Retrieve_NotFound(t *testing.T) {
+	ctx := context.Background()
+
+	user := TestedStruct{}
+	err := fuego.Document("users", "unknown").Retrieve(ctx, &user)
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, document.ErrDocumentNotExist) {
+		t.Fatalf("Expected a NotFound error, got %v", err)
+	}
+
+	var fe *Error
+	if !errors.As(err, &fe) || fe.Op != "Document.Retrieve" || fe.Path != "users/unknown" {
+		t.Fatalf("Unexpected error details: %v", err)
+	}
+}
+
 func TestIntegration_Collection_Retrieve(t *testing.T) {
 	ctx := context.Background()
 
@@ -197,6 +410,118 @@ func TestIntegration_String_Update(t *testing.T) {
 	fmt.Println("New FirstName: ", value)
 }
 
+func TestIntegration_String_CompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+
+	field := fuego.Document("users", "jsmith").String("Status")
+	defer field.Delete(ctx)
+
+	if err := field.Update(ctx, "pending"); err != nil {
+		t.Fatal(err)
+	}
+
+	swapped, err := field.CompareAndSwap(ctx, "pending", "processing")
+	if err != nil || !swapped {
+		t.Fatalf("The swap is expected to happen, got %t (%v)", swapped, err)
+	}
+
+	swapped, err = field.CompareAndSwap(ctx, "pending", "processing")
+	if err != nil || swapped {
+		t.Fatalf("The swap is not expected to happen, got %t (%v)", swapped, err)
+	}
+
+	updated, err := field.UpdateIfIn(ctx, []string{"shipped", "delivered"}, "cancelled")
+	if err != nil || updated {
+		t.Fatalf("The update is not expected to happen, got %t (%v)", updated, err)
+	}
+
+	updated, err = field.UpdateIfIn(ctx, []string{"pending", "processing"}, "cancelled")
+	if err != nil || !updated {
+		t.Fatalf("The update is expected to happen, got %t (%v)", updated, err)
+	}
+
+	value, err := field.Retrieve(ctx)
+	if err != nil || value != "cancelled" {
+		t.Fatalf("Got %s (%v) but expected cancelled", value, err)
+	}
+}
+
+func TestIntegration_State_Transition(t *testing.T) {
+	ctx := context.Background()
+
+	onboarding := fuego.Document("users", "jsmith").State("Onboarding", document.Transitions{
+		"":        {"started"},
+		"started": {"completed"},
+	})
+	defer fuego.Document("users", "jsmith").Update(ctx,
+		document.Update{Path: "Onboarding", Value: document.Delete},
+		document.Update{Path: "OnboardingHistory", Value: document.Delete},
+	)
+
+	if err := onboarding.Transition(ctx, "started"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := onboarding.Transition(ctx, "started")
+	var transitionErr *document.TransitionError
+	if !errors.As(err, &transitionErr) || !errors.Is(err, ErrConflict) || transitionErr.From != "started" {
+		t.Fatalf("The error is expected to be a Conflict error wrapping a *TransitionError, got %v.", err)
+	}
+
+	// An illegal transition is never worth retrying
+	if !errors.Is(err, document.ErrIllegalTransition) || status.Code(err) == codes.Aborted {
+		t.Fatalf("The error is expected to match ErrIllegalTransition without the Aborted code, got %v (%s).", err, status.Code(err))
+	}
+
+	if err := onboarding.Transition(ctx, "completed"); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := onboarding.Retrieve(ctx)
+	if err != nil || value != "completed" {
+		t.Fatalf("Got %s (%v) but expected completed", value, err)
+	}
+
+	history, err := onboarding.History(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 2 || history[0].To != "started" || history[1].From != "started" || history[1].To != "completed" {
+		t.Fatalf("Unexpected history: %+v", history)
+	}
+}
+
+func TestIntegration_MapKey_State(t *testing.T) {
+	ctx := context.Background()
+
+	status := fuego.Document("users", "jsmith").Map("Apps").Key("web.app").State(document.Transitions{
+		"":       {"active"},
+		"active": {"paused"},
+		"paused": {"active"},
+	})
+	defer fuego.Document("users", "jsmith").Map("Apps").Delete(ctx)
+
+	if status.HistoryField != "Apps.`web.appHistory`" {
+		t.Fatalf("Got %s but expected %s", status.HistoryField, "Apps.`web.appHistory`")
+	}
+
+	for _, to := range []string{"active", "paused", "active"} {
+		if err := status.Transition(ctx, to); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := status.History(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 3 || history[2].From != "paused" || history[2].To != "active" {
+		t.Fatalf("Unexpected history: %+v", history)
+	}
+}
+
 func TestIntegration_Boolean_Retrieve(t *testing.T) {
 	ctx := context.Background()
 
@@ -236,6 +561,52 @@ func TestIntegration_Boolean_Update(t *testing.T) {
 	fmt.Println("New Premium: ", value)
 }
 
+func TestIntegration_Boolean_Toggle(t *testing.T) {
+	ctx := context.Background()
+
+	field := fuego.Document("users", "jsmith").Boolean("Beta")
+	defer field.Delete(ctx)
+
+	// The field doesn't exist yet
+	value, err := field.Toggle(ctx)
+	if err != nil || !value {
+		t.Fatalf("Got %t (%v) but expected true", value, err)
+	}
+
+	value, err = field.Toggle(ctx)
+	if err != nil || value {
+		t.Fatalf("Got %t (%v) but expected false", value, err)
+	}
+}
+
+func TestIntegration_Boolean_SetIf(t *testing.T) {
+	ctx := context.Background()
+
+	field := fuego.Document("users", "jsmith").Boolean("WelcomeEmailSent")
+	defer field.Delete(ctx)
+
+	var claims int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claimed, err := field.SetIf(ctx, false, true)
+			if err != nil {
+				t.Error(err)
+			}
+			if claimed {
+				atomic.AddInt32(&claims, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if claims != 1 {
+		t.Fatalf("The flag is expected to be claimed once, got %d.", claims)
+	}
+}
+
 func TestIntegration_Number_Retrieve(t *testing.T) {
 	ctx := context.Background()
 
@@ -327,6 +698,104 @@ func TestIntegration_Number_Decrement(t *testing.T) {
 	fmt.Println("Decremented Age: ", value)
 }
 
+func TestIntegration_Number_Transforms(t *testing.T) {
+	ctx := context.Background()
+
+	points := fuego.Document("users", "jsmith").Number("Points")
+	defer points.Delete(ctx)
+
+	// The field doesn't exist yet: it is set to delta
+	if err := points.Add(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := points.Multiply(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := points.Min(ctx, 25); err != nil {
+		t.Fatal(err)
+	}
+	if err := points.Max(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := points.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != 25 {
+		t.Fatalf("Got %d but expected %d", value, 25)
+	}
+
+	err = points.Multiply(ctx, 2, document.IfUpdatedAt(time.Unix(0, 0)))
+	if !errors.Is(err, ErrConflict) || !errors.Is(err, document.ErrPreconditionFailed) {
+		t.Fatalf("The error is expected to be a Conflict error, got %v.", err)
+	}
+}
+
+func TestIntegration_Float(t *testing.T) {
+	ctx := context.Background()
+
+	balance := fuego.Document("users", "jsmith").Float("Balance")
+	defer balance.Delete(ctx)
+
+	if err := balance.Update(ctx, 10.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := balance.Add(ctx, -0.25); err != nil {
+		t.Fatal(err)
+	}
+	if err := balance.Multiply(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := balance.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != 20.5 {
+		t.Fatalf("Got %f but expected %f", value, 20.5)
+	}
+}
+
+func TestIntegration_Number_Retrieve_TypeMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	doc := fuego.Document("types", "mixed")
+	_, err := doc.Upsert(ctx, map[string]interface{}{
+		"Age":  float64(42),
+		"Name": nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Delete(ctx)
+
+	// 1. Strict (default)
+	_, err = doc.Number("Age").Retrieve(ctx)
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("Expected a TypeMismatch error, got %v", err)
+	}
+
+	// 2. Lenient
+	lenient := New(fuego.FirestoreClient, WithConversions(document.LenientConversions))
+
+	value, err := lenient.Document("types", "mixed").Number("Age").Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != 42 {
+		t.Fatalf("Got %d but expected %d", value, 42)
+	}
+
+	name, err := lenient.Document("types", "mixed").String("Name").Retrieve(ctx)
+	if err != nil || name != "" {
+		t.Fatalf("Got %q, %v but expected an empty string", name, err)
+	}
+}
+
 func TestIntegration_Timestamp_Retrieve(t *testing.T) {
 	ctx := context.Background()
 
@@ -366,101 +835,452 @@ func TestIntegration_Timestamp_Update(t *testing.T) {
 	fmt.Println("New LastSeenAt: ", value)
 }
 
-func TestIntegration_Map_Merge(t *testing.T) {
+func TestIntegration_Timestamp_Touch(t *testing.T) {
 	ctx := context.Background()
 
-	expectedAndroidToken := "AND_456"
-	expectedNewMap := map[string]interface{}{
-		"Android": expectedAndroidToken,
-	}
+	field := fuego.Document("users", "jsmith").Timestamp("LastSeenAt")
 
-	err := fuego.Document("users", "jsmith").
-		Map("Tokens").
-		Merge(ctx, expectedNewMap)
+	before := time.Now().Add(-time.Minute)
+	touchedAt, err := field.Touch(ctx)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
-	resultMap, err := fuego.Document("users", "jsmith").
-		Map("Tokens").
-		Retrieve(ctx)
-	if err != nil {
-		t.Fatalf(err.Error())
+	if touchedAt.Before(before) {
+		t.Fatalf("The server time is expected to be recent, got %v.", touchedAt)
 	}
 
-	// Making sure the map has been merged
-	if len(resultMap) != 2 {
-		t.Fatalf("The map should have been merged, got %d elements", len(resultMap))
+	value, err := field.Retrieve(ctx, "UTC")
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	androidToken := resultMap["Android"]
-	if androidToken != expectedAndroidToken {
-		t.Fatalf("Got %s but expected %s", androidToken, expectedAndroidToken)
+	if !value.Equal(touchedAt) {
+		t.Fatalf("Got %v but expected %v", value, touchedAt)
 	}
 }
 
-/* TODO: Finish the work on maps
-func TestIntegration_Field_OverrideMapWith(t *testing.T) {
+func TestIntegration_Timestamp_RetrieveIn(t *testing.T) {
 	ctx := context.Background()
 
-	expectedAndroidToken := "AND_789"
-	expectedNewMap := map[string]interface{}{
-		"Android": expectedAndroidToken,
+	loc, err := fuego.Location("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	err := fuego.Document("users", "jsmith").
-		Field("Tokens").
-		OverrideMapWith(ctx, expectedNewMap)
+	field := fuego.Document("users", "jsmith").Timestamp("LastSeenAt")
+	value, err := field.RetrieveIn(ctx, loc)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
-	value, err := fuego.Document("users", "jsmith").
-		Field("Tokens").
-		Retrieve(ctx)
-	if err != nil {
-		t.Fatalf(err.Error())
+	if value.Location() != loc {
+		t.Fatalf("Got %v but expected %v", value.Location(), loc)
 	}
 
-	m := value.(map[string]interface{})
+	utc, err := field.RetrieveUTC(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	// Making sure the map has been overridden
-	if len(m) != 1 {
-		t.Fatalf("The map should have been overridden, got %d elements", len(m))
+	if utc.Location() != time.UTC || !utc.Equal(value) {
+		t.Fatalf("Got %v but expected %v", utc, value.UTC())
 	}
 
-	androidToken := m["Android"]
-	if androidToken != expectedAndroidToken {
-		t.Fatalf("Got %s but expected %s", androidToken, expectedAndroidToken)
+	if _, err := fuego.Location("Nowhere/Somewhere"); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("The error is expected to be an InvalidArgument error, got %v.", err)
 	}
-}*/
+}
 
-func TestIntegration_Array_Append(t *testing.T) {
+func TestIntegration_Collection_TimestampRange(t *testing.T) {
 	ctx := context.Background()
 
-	expectedArraySizeAfterWrite := 3
-
-	err := fuego.Document("users", "jsmith").
-		Array("Address").
-		Append(ctx, []interface{}{"4th Floor"})
+	loc, err := fuego.Location("America/Los_Angeles")
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
-	values, err := fuego.Document("users", "jsmith").
-		Array("Address").
-		Retrieve(ctx)
+	if _, err := fuego.Document("users", "jsmith").Timestamp("LastSeenAt").Touch(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	users := fuego.Collection("users")
+	values, err := users.RetrieveWith(ctx, &TestedStruct{}, collection.DayRange("LastSeenAt", time.Now(), loc).Query(users.Query))
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
-	// Making sure the data has been added
-	if len(values) != expectedArraySizeAfterWrite {
-		t.Fatalf("The array is expected to have %d elements, got %d.", expectedArraySizeAfterWrite, len(values))
+	if len(values) == 0 {
+		t.Fatal("The user seen today is expected to be retrieved.")
 	}
 }
 
-func TestIntegration_Array_Override(t *testing.T) {
+func TestIntegration_GeoPoint(t *testing.T) {
+	ctx := context.Background()
+
+	expectedValue := &latlng.LatLng{Latitude: 48.8584, Longitude: 2.2945}
+	if err := fuego.Document("users", "jsmith").GeoPoint("Home").Update(ctx, expectedValue); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := fuego.Document("users", "jsmith").GeoPoint("Home").Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.GetLatitude() != expectedValue.GetLatitude() || value.GetLongitude() != expectedValue.GetLongitude() {
+		t.Fatalf("The field is expected to be %v, got %v.", expectedValue, value)
+	}
+
+	if _, err := fuego.Document("users", "jsmith").GeoPoint("FirstName").Retrieve(ctx); !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("A TypeMismatch error is expected, got %v.", err)
+	}
+}
+
+func TestIntegration_Bytes(t *testing.T) {
+	ctx := context.Background()
+
+	expectedValue := []byte{0x00, 0x01, 0xfe, 0xff}
+	if err := fuego.Document("users", "jsmith").Bytes("Avatar").Update(ctx, expectedValue); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := fuego.Document("users", "jsmith").Bytes("Avatar").Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(value, expectedValue) {
+		t.Fatalf("The field is expected to be %v, got %v.", expectedValue, value)
+	}
+
+	err = fuego.Document("users", "jsmith").Bytes("Avatar").WithMaxSize(2).Update(ctx, expectedValue)
+	if !errors.Is(err, document.ErrSizeLimitExceeded) || !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("An InvalidArgument error is expected, got %v.", err)
+	}
+}
+
+func TestIntegration_Reference(t *testing.T) {
+	ctx := context.Background()
+
+	if err := fuego.Document("users", "jsmith").Reference("Manager").Update(ctx, fuego.Document("users", "jsmith")); err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := fuego.Document("users", "jsmith").Reference("Manager").Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if manager.GetID() != "jsmith" {
+		t.Fatalf("The referenced document is expected to be jsmith, got %s.", manager.GetID())
+	}
+
+	user := TestedStruct{}
+	if err := manager.Retrieve(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fuego.Document("users", "jsmith").Reference("FirstName").Retrieve(ctx); !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("A TypeMismatch error is expected, got %v.", err)
+	}
+
+	// A nil *FirestoreDocument sets the field to null
+	if err := fuego.Document("users", "jsmith").Reference("Manager").Update(ctx, (*document.FirestoreDocument)(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	null, err := fuego.Document("users", "jsmith").IsNull(ctx, "Manager")
+	if err != nil || !null {
+		t.Fatalf("The field is expected to be null, got %t (%v).", null, err)
+	}
+}
+
+func TestIntegration_Document_Null(t *testing.T) {
+	ctx := context.Background()
+
+	if err := fuego.Document("users", "jsmith").SetNull(ctx, "MiddleName"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		field string
+		want  bool
+	}{
+		{field: "MiddleName", want: true},
+		{field: "FirstName", want: false},
+		{field: "NotAField", want: false},
+	}
+
+	for _, test := range tests {
+		null, err := fuego.Document("users", "jsmith").IsNull(ctx, test.field)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if null != test.want {
+			t.Fatalf("%s -> IsNull is expected to be %t.", test.field, test.want)
+		}
+	}
+}
+
+func TestIntegration_Collection_WithinRadius(t *testing.T) {
+	ctx := context.Background()
+
+	type Shop struct {
+		Name     string    `firestore:"Name"`
+		Location geo.Point `firestore:"Location"`
+	}
+
+	shops := map[string]Shop{
+		"eiffel":    {Name: "Eiffel Tower", Location: geo.NewPoint(48.8584, 2.2945)},
+		"louvre":    {Name: "Louvre", Location: geo.NewPoint(48.8606, 2.3376)},
+		"london":    {Name: "London", Location: geo.NewPoint(51.5074, -0.1278)},
+		"notredame": {Name: "Notre-Dame", Location: geo.NewPoint(48.8530, 2.3499)},
+	}
+	for id, shop := range shops {
+		if _, err := fuego.Document("shops", id).Upsert(ctx, shop); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer fuego.Collection("shops").DeleteAll(ctx)
+
+	center := &latlng.LatLng{Latitude: 48.8566, Longitude: 2.3522} // Hôtel de Ville
+	values, err := fuego.Collection("shops").WithinRadius(ctx, &Shop{}, "Location", center, 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(values) != 2 || values[0].(*Shop).Name != "Notre-Dame" || values[1].(*Shop).Name != "Louvre" {
+		t.Fatalf("Notre-Dame and the Louvre are expected to be retrieved (nearest first), got %d values.", len(values))
+	}
+
+	_, err = fuego.Collection("shops").WithinRadius(ctx, &Shop{}, "Location", center, 0)
+	if !errors.Is(err, geo.ErrInvalidRadius) || !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("An InvalidArgument error is expected, got %v.", err)
+	}
+}
+
+func TestIntegration_Map_Merge(t *testing.T) {
+	ctx := context.Background()
+
+	expectedAndroidToken := "AND_456"
+	expectedNewMap := map[string]interface{}{
+		"Android": expectedAndroidToken,
+	}
+
+	err := fuego.Document("users", "jsmith").
+		Map("Tokens").
+		Merge(ctx, expectedNewMap)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	resultMap, err := fuego.Document("users", "jsmith").
+		Map("Tokens").
+		Retrieve(ctx)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Making sure the map has been merged
+	if len(resultMap) != 2 {
+		t.Fatalf("The map should have been merged, got %d elements", len(resultMap))
+	}
+
+	androidToken := resultMap["Android"]
+	if androidToken != expectedAndroidToken {
+		t.Fatalf("Got %s but expected %s", androidToken, expectedAndroidToken)
+	}
+}
+
+func TestIntegration_Map_DeleteKeys(t *testing.T) {
+	ctx := context.Background()
+
+	tokens := fuego.Document("users", "jsmith").Map("Tokens")
+	err := tokens.Merge(ctx, map[string]interface{}{"Web": "WEB_123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tokens.DeleteKeys(ctx, []string{"Web"}); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := tokens.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := values["Web"]; ok {
+		t.Fatal("The key is expected to be deleted.")
+	}
+
+	err = tokens.DeleteKeys(ctx, []string{"Android"}, document.IfUpdatedAt(time.Unix(0, 0)))
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("The error is expected to be a Conflict error, got %v.", err)
+	}
+}
+
+func TestIntegration_String_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	field := fuego.Document("users", "jsmith").String("Nickname")
+	if err := field.Update(ctx, "Johnny"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := field.Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := field.Retrieve(ctx)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("The error is expected to be a NotFound error, got %v.", err)
+	}
+}
+
+func TestIntegration_Map_Key(t *testing.T) {
+	ctx := context.Background()
+
+	key := fuego.Document("users", "jsmith").Map("Tokens").Key("web.app")
+	if err := key.String().Update(ctx, "WEB_123"); err != nil {
+		t.Fatal(err)
+	}
+	defer key.String().Delete(ctx)
+
+	value, err := key.String().Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != "WEB_123" {
+		t.Fatalf("The key is expected to be WEB_123, got %s.", value)
+	}
+
+	// Making sure the dot is part of the key
+	values, err := fuego.Document("users", "jsmith").Map("Tokens").Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values["web.app"] != "WEB_123" {
+		t.Fatalf("The map is expected to hold the key web.app, got %v.", values)
+	}
+}
+
+func TestIntegration_Map_Override(t *testing.T) {
+	ctx := context.Background()
+
+	tokens := fuego.Document("users", "jsmith").Map("Tokens")
+	err := tokens.Merge(ctx, map[string]interface{}{"Web": "WEB_123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedAndroidToken := "AND_789"
+	err = tokens.Override(ctx, map[string]interface{}{"Android": expectedAndroidToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := tokens.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Making sure the stale keys have been removed
+	if len(values) != 1 {
+		t.Fatalf("The map should have been overridden, got %v", values)
+	}
+
+	if values["Android"] != expectedAndroidToken {
+		t.Fatalf("Got %s but expected %s", values["Android"], expectedAndroidToken)
+	}
+
+	// Making sure the other fields are untouched
+	firstName, err := fuego.Document("users", "jsmith").String("FirstName").Retrieve(ctx)
+	if err != nil || len(firstName) == 0 {
+		t.Fatalf("The other fields are expected to be untouched, got %q (%v)", firstName, err)
+	}
+}
+
+func TestIntegration_Map_Merge_Nested(t *testing.T) {
+	ctx := context.Background()
+
+	settings := fuego.Document("users", "jsmith").Map("Settings")
+	err := settings.Override(ctx, map[string]interface{}{
+		"Theme":         "dark",
+		"Notifications": map[string]interface{}{"Email": true, "Push": true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer settings.Delete(ctx)
+
+	// Deep merge: the sibling keys of nested maps are kept
+	err = settings.Merge(ctx, map[string]interface{}{
+		"Notifications": map[string]interface{}{"Push": false},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := settings.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notifications := values["Notifications"].(map[string]interface{})
+	if values["Theme"] != "dark" || notifications["Email"] != true || notifications["Push"] != false {
+		t.Fatalf("The nested map should have been merged, got %v", values)
+	}
+
+	// Shallow merge: nested maps are replaced
+	err = settings.Merge(ctx, map[string]interface{}{
+		"Notifications": map[string]interface{}{"Push": true},
+	}, document.Shallow, document.IfExists)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err = settings.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notifications = values["Notifications"].(map[string]interface{})
+	if values["Theme"] != "dark" || len(notifications) != 1 || notifications["Push"] != true {
+		t.Fatalf("The nested map should have been replaced, got %v", values)
+	}
+}
+
+func TestIntegration_Array_Append(t *testing.T) {
+	ctx := context.Background()
+
+	expectedArraySizeAfterWrite := 3
+
+	err := fuego.Document("users", "jsmith").
+		Array("Address").
+		Append(ctx, []interface{}{"4th Floor"})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	values, err := fuego.Document("users", "jsmith").
+		Array("Address").
+		Retrieve(ctx)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Making sure the data has been added
+	if len(values) != expectedArraySizeAfterWrite {
+		t.Fatalf("The array is expected to have %d elements, got %d.", expectedArraySizeAfterWrite, len(values))
+	}
+}
+
+func TestIntegration_Array_Override(t *testing.T) {
 	ctx := context.Background()
 
 	expectedArraySizeAfterWrite := 1
@@ -485,6 +1305,208 @@ func TestIntegration_Array_Override(t *testing.T) {
 	}
 }
 
+func TestIntegration_Array_SetOperations(t *testing.T) {
+	ctx := context.Background()
+
+	tags := fuego.Document("users", "jsmith").Array("Tags")
+	defer tags.Delete(ctx)
+
+	// The field doesn't exist yet
+	if count, err := tags.Len(ctx); err != nil || count != 0 {
+		t.Fatalf("Got %d (%v) but expected 0", count, err)
+	}
+
+	if err := tags.InsertAt(ctx, 0, []interface{}{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tags.Union(ctx, []interface{}{"b", "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tags.InsertAt(ctx, 0, []interface{}{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tags.RemoveAt(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := tags.Remove(ctx, []interface{}{"a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := tags.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(values) != 1 || values[0] != "c" {
+		t.Fatalf("Got %v but expected [c]", values)
+	}
+
+	found, err := tags.Contains(ctx, "c")
+	if err != nil || !found {
+		t.Fatalf("The array is expected to contain c, got %t (%v)", found, err)
+	}
+
+	removed, err := tags.RemoveWhere(ctx, func(item interface{}) bool {
+		return item == "c"
+	})
+	if err != nil || removed != 1 {
+		t.Fatalf("Got %d (%v) but expected 1", removed, err)
+	}
+
+	err = tags.RemoveAt(ctx, 0)
+	if !errors.Is(err, document.ErrIndexOutOfRange) || !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("The error is expected to be an InvalidArgument error, got %v.", err)
+	}
+}
+
+func TestIntegration_Array_Push(t *testing.T) {
+	ctx := context.Background()
+
+	recent := fuego.Document("users", "jsmith").Array("RecentlyViewed")
+	defer recent.Delete(ctx)
+
+	dedupe := document.DedupeBy(func(item interface{}) interface{} { return item })
+	for _, item := range []string{"a", "b", "c", "a", "d"} {
+		if err := recent.Push(ctx, []interface{}{item}, 3, dedupe); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	values, err := recent.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{"c", "a", "d"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("Got %v but expected %v", values, expected)
+	}
+}
+
+func TestIntegration_Field_Struct(t *testing.T) {
+	ctx := context.Background()
+
+	type Plan struct {
+		Name  string   `firestore:"Name"`
+		Seats int      `firestore:"Seats"`
+		Tags  []string `firestore:"Tags"`
+	}
+
+	expectedValue := Plan{Name: "Business", Seats: 12, Tags: []string{"annual"}}
+
+	field := document.Field[Plan](fuego.Document("users", "jsmith"), "Plan")
+	if err := field.Set(ctx, expectedValue); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := field.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Name != expectedValue.Name || value.Seats != expectedValue.Seats || len(value.Tags) != 1 {
+		t.Fatalf("The field is expected to be %+v, got %+v.", expectedValue, value)
+	}
+}
+
+func TestIntegration_TypedArray(t *testing.T) {
+	ctx := context.Background()
+
+	type Address struct {
+		Street string                 `firestore:"Street"`
+		City   string                 `firestore:"City"`
+		Extra  map[string]interface{} `firestore:"Extra,omitempty"`
+	}
+
+	addresses := document.ArrayOf[Address](fuego.Document("users", "jsmith"), "Addresses")
+	defer addresses.Delete(ctx)
+
+	dublin := Address{Street: "1 Main Street", City: "Dublin", Extra: map[string]interface{}{"Floor": int64(2)}}
+	paris := Address{Street: "2 Rue de Rivoli", City: "Paris"}
+
+	// As with Array, appending to a missing field fails
+	if err := addresses.Append(ctx, []Address{dublin}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("The error is expected to be a NotFound error, got %v.", err)
+	}
+
+	if err := addresses.Override(ctx, []Address{dublin}); err != nil {
+		t.Fatal(err)
+	}
+	if err := addresses.Append(ctx, []Address{paris}); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := addresses.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(values, []Address{dublin, paris}) {
+		t.Fatalf("Got %+v but expected %+v", values, []Address{dublin, paris})
+	}
+
+	removed, err := addresses.RemoveWhere(ctx, func(a Address) bool {
+		return a.City == "Paris"
+	})
+	if err != nil || removed != 1 {
+		t.Fatalf("Got %d (%v) but expected 1", removed, err)
+	}
+
+	found, err := addresses.Contains(ctx, dublin)
+	if err != nil || !found {
+		t.Fatalf("The array is expected to contain %+v, got %t (%v)", dublin, found, err)
+	}
+}
+
+func TestIntegration_Field_CompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+
+	field := document.Field[string](fuego.Document("users", "jsmith"), "Status")
+	if err := field.Set(ctx, "pending"); err != nil {
+		t.Fatal(err)
+	}
+
+	swapped, err := field.CompareAndSwap(ctx, "pending", "processing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !swapped {
+		t.Fatal("The value is expected to be swapped.")
+	}
+
+	swapped, err = field.CompareAndSwap(ctx, "pending", "cancelled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapped {
+		t.Fatal("The value is not expected to be swapped.")
+	}
+
+	if err := field.Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = field.Get(ctx)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("The error is expected to be a NotFound error, got %v.", err)
+	}
+}
+
+func TestIntegration_Field_OtherDocument(t *testing.T) {
+	ctx := context.Background()
+
+	// A Document implementation other than *document.FirestoreDocument
+	type wrappedDocument struct {
+		document.Document
+	}
+
+	field := document.Field[string](wrappedDocument{fuego.Document("users", "jsmith")}, "Status")
+	_, err := field.CompareAndSwap(ctx, "pending", "processing")
+	if !errors.Is(err, document.ErrNoClient) || !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("An InvalidArgument error is expected, got %v.", err)
+	}
+}
+
 func TestIntegration_Document_Delete(t *testing.T) {
 	ctx := context.Background()
 
@@ -512,13 +1534,109 @@ func TestIntegration_Document_Create_Many(t *testing.T) {
 			Premium:    false,
 		}
 
-		err := fuego.DocumentWithGeneratedID("users").Create(ctx, user)
+		_, err := fuego.DocumentWithGeneratedID("users").Create(ctx, user)
 		if err != nil {
 			t.Fatalf(err.Error())
 		}
 	}
 }
 
+func TestIntegration_Document_GeneratedID(t *testing.T) {
+	ctx := context.Background()
+
+	doc := fuego.DocumentWithGeneratedID("users")
+	id, err := doc.Create(ctx, TestedStruct{FirstName: "Generated"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Delete(ctx)
+
+	if id != doc.GetID() {
+		t.Fatalf("The returned ID is expected to be %s, got %s.", doc.GetID(), id)
+	}
+
+	// Making sure the same document is addressed
+	user := TestedStruct{}
+	if err := doc.Retrieve(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	if user.FirstName != "Generated" {
+		t.Fatalf("The first name is expected to be Generated, got %s.", user.FirstName)
+	}
+}
+
+func TestIntegration_Document_HashID(t *testing.T) {
+	ctx := context.Background()
+
+	client := New(fuego.FirestoreClient, WithIDStrategy(HashID("EmailAddress")))
+	user := TestedStruct{FirstName: "Hashed", EmailAddress: "hashed@email.com"}
+
+	doc := client.DocumentWithGeneratedID("users")
+	if _, err := doc.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Delete(ctx)
+
+	// The same data gets the same ID
+	_, err := client.DocumentWithGeneratedID("users").Create(ctx, user)
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("The error is expected to be an AlreadyExists error, got %v.", err)
+	}
+}
+
+func TestIntegration_Counter(t *testing.T) {
+	ctx := context.Background()
+
+	counter := fuego.Counter("counters", "likes", 5)
+	defer fuego.Collection("counters/likes/shards").DeleteAll(ctx)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := counter.Increment(ctx, 2); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, err := counter.Value(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != 40 {
+		t.Fatalf("Got %d but expected %d", value, 40)
+	}
+
+	// Resharding preserves the value
+	if err := counter.Reshard(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err = counter.Value(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != 40 {
+		t.Fatalf("Got %d but expected %d after resharding", value, 40)
+	}
+
+	if err := counter.Reshard(ctx, 0); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("The error is expected to be an InvalidArgument error, got %v.", err)
+	}
+
+	// The number of shards is validated when used
+	err = fuego.Counter("counters", "likes", 0).Increment(ctx, 1)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("The error is expected to be an InvalidArgument error, got %v.", err)
+	}
+}
+
 func TestIntegration_Collection_SetForAll(t *testing.T) {
 	ctx := context.Background()
 
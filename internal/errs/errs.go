@@ -0,0 +1,214 @@
+// Package errs provides the error type returned by the fuego packages.
+package errs
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kind classifies an Error.
+type Kind int
+
+const (
+	// Unknown is the kind of the errors that couldn't be classified.
+	Unknown Kind = iota
+
+	// NotFound indicates that a document (or field) doesn't exist.
+	NotFound
+
+	// AlreadyExists indicates that a document already exists.
+	AlreadyExists
+
+	// Conflict indicates that a precondition failed or that a transaction has been aborted because of contention.
+	//
+	// Its gRPC code is FailedPrecondition (i.e. not worth retrying), unless it wraps a gRPC error (e.g. Aborted).
+	Conflict
+
+	// PermissionDenied indicates that the caller is not allowed to perform the operation.
+	PermissionDenied
+
+	// Unavailable indicates a transient failure (e.g. the service is unavailable, a quota is exhausted, a deadline expired).
+	Unavailable
+
+	// InvalidArgument indicates that the operation is invalid (e.g. an invalid field path or value).
+	InvalidArgument
+
+	// TypeMismatch indicates that a value doesn't have the expected type.
+	TypeMismatch
+)
+
+var (
+	// ErrNotFound is matched (with errors.Is) by the errors of kind NotFound.
+	ErrNotFound = errors.New("fuego: not found")
+
+	// ErrAlreadyExists is matched (with errors.Is) by the errors of kind AlreadyExists.
+	ErrAlreadyExists = errors.New("fuego: already exists")
+
+	// ErrConflict is matched (with errors.Is) by the errors of kind Conflict.
+	ErrConflict = errors.New("fuego: conflict")
+
+	// ErrPermissionDenied is matched (with errors.Is) by the errors of kind PermissionDenied.
+	ErrPermissionDenied = errors.New("fuego: permission denied")
+
+	// ErrUnavailable is matched (with errors.Is) by the errors of kind Unavailable.
+	ErrUnavailable = errors.New("fuego: unavailable")
+
+	// ErrInvalidArgument is matched (with errors.Is) by the errors of kind InvalidArgument.
+	ErrInvalidArgument = errors.New("fuego: invalid argument")
+
+	// ErrTypeMismatch is matched (with errors.Is) by the errors of kind TypeMismatch.
+	ErrTypeMismatch = errors.New("fuego: type mismatch")
+)
+
+var kinds = map[Kind]struct {
+	name     string
+	sentinel error
+	code     codes.Code
+}{
+	Unknown:          {"unknown", nil, codes.Unknown},
+	NotFound:         {"not found", ErrNotFound, codes.NotFound},
+	AlreadyExists:    {"already exists", ErrAlreadyExists, codes.AlreadyExists},
+	Conflict:         {"conflict", ErrConflict, codes.FailedPrecondition},
+	PermissionDenied: {"permission denied", ErrPermissionDenied, codes.PermissionDenied},
+	Unavailable:      {"unavailable", ErrUnavailable, codes.Unavailable},
+	InvalidArgument:  {"invalid argument", ErrInvalidArgument, codes.InvalidArgument},
+	TypeMismatch:     {"type mismatch", ErrTypeMismatch, codes.InvalidArgument},
+}
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	return kinds[k].name
+}
+
+// Error is the error returned by the fuego operations.
+//
+// It can be matched with errors.Is against the sentinel error of its kind (e.g. ErrNotFound)
+// as well as against the error it wraps (e.g. document.ErrDocumentNotExist).
+type Error struct {
+
+	// Op is the operation that failed (e.g. "String.Update").
+	Op string
+
+	// Path is the path of the document or collection (e.g. "users/jsmith").
+	Path string
+
+	// Field is the name of the field (if any).
+	Field string
+
+	// Kind classifies the error.
+	Kind Kind
+
+	// Err is the underlying error.
+	Err error
+}
+
+// New creates and returns a new Error.
+func New(op, path, field string, kind Kind, err error) *Error {
+	return &Error{
+		Op:    op,
+		Path:  ShortPath(path),
+		Field: field,
+		Kind:  kind,
+		Err:   err,
+	}
+}
+
+// Wrap classifies err and wraps it in an Error.
+//
+// nil is returned if err is nil. err is returned as is if it already is an Error.
+func Wrap(op, path, field string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	return New(op, path, field, Classify(err), err)
+}
+
+// Classify returns the kind of a given error.
+func Classify(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Unavailable
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound:
+		return NotFound
+	case codes.AlreadyExists:
+		return AlreadyExists
+	case codes.Aborted, codes.FailedPrecondition:
+		return Conflict
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return PermissionDenied
+	case codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded:
+		return Unavailable
+	case codes.InvalidArgument, codes.OutOfRange:
+		return InvalidArgument
+	}
+
+	return Unknown
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("fuego: ")
+	b.WriteString(e.Op)
+	if len(e.Path) > 0 {
+		b.WriteString(" ")
+		b.WriteString(e.Path)
+	}
+	if len(e.Field) > 0 {
+		b.WriteString(" (")
+		b.WriteString(e.Field)
+		b.WriteString(")")
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Kind.String())
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is the sentinel error of the kind of e.
+func (e *Error) Is(target error) bool {
+	return target != nil && target == kinds[e.Kind].sentinel
+}
+
+// GRPCStatus returns the gRPC status of the underlying error, or one derived from the kind,
+// so that status.Code keeps working on an Error.
+func (e *Error) GRPCStatus() *status.Status {
+	if s, ok := status.FromError(e.Err); ok && e.Err != nil {
+		return s
+	}
+	return status.New(kinds[e.Kind].code, e.Error())
+}
+
+// ShortPath strips the project and database from a resource path
+// (e.g. "projects/p/databases/(default)/documents/users/jsmith" becomes "users/jsmith").
+func ShortPath(path string) string {
+	const sep = "/documents/"
+	if i := strings.Index(path, sep); i >= 0 {
+		return path[i+len(sep):]
+	}
+	return path
+}
@@ -0,0 +1,172 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassify(t *testing.T) {
+
+	tests := []struct {
+		description string
+		with        error
+		want        Kind
+	}{
+		{
+			description: "NotFound",
+			with:        status.Error(codes.NotFound, ""),
+			want:        NotFound,
+		},
+		{
+			description: "AlreadyExists",
+			with:        status.Error(codes.AlreadyExists, ""),
+			want:        AlreadyExists,
+		},
+		{
+			description: "FailedPrecondition",
+			with:        status.Error(codes.FailedPrecondition, ""),
+			want:        Conflict,
+		},
+		{
+			description: "Aborted",
+			with:        status.Error(codes.Aborted, ""),
+			want:        Conflict,
+		},
+		{
+			description: "Unauthenticated",
+			with:        status.Error(codes.Unauthenticated, ""),
+			want:        PermissionDenied,
+		},
+		{
+			description: "ResourceExhausted",
+			with:        status.Error(codes.ResourceExhausted, ""),
+			want:        Unavailable,
+		},
+		{
+			description: "Context deadline",
+			with:        fmt.Errorf("wrapped: %w", context.DeadlineExceeded),
+			want:        Unavailable,
+		},
+		{
+			description: "InvalidArgument",
+			with:        status.Error(codes.InvalidArgument, ""),
+			want:        InvalidArgument,
+		},
+		{
+			description: "Already classified",
+			with:        New("Number.Retrieve", "users/jsmith", "Age", TypeMismatch, nil),
+			want:        TypeMismatch,
+		},
+		{
+			description: "Not a gRPC error",
+			with:        errors.New("boom"),
+			want:        Unknown,
+		},
+	}
+
+	for _, test := range tests {
+		result := Classify(test.with)
+
+		if result != test.want {
+			t.Fatalf("%s -> Got %s but expected %s", test.description, result, test.want)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	sentinel := errors.New("document: doesn't exist")
+	err := Wrap("String.Retrieve", "projects/p/databases/(default)/documents/users/jsmith", "FirstName",
+		fmt.Errorf("%w: %w", sentinel, status.Error(codes.NotFound, "missing")))
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Expected an *Error, got %T", err)
+	}
+
+	if e.Op != "String.Retrieve" || e.Path != "users/jsmith" || e.Field != "FirstName" || e.Kind != NotFound {
+		t.Fatalf("Unexpected error details: %+v", e)
+	}
+
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, sentinel) {
+		t.Fatalf("Expected %v to match ErrNotFound and the wrapped sentinel", err)
+	}
+
+	if errors.Is(err, ErrConflict) {
+		t.Fatalf("Didn't expect %v to match ErrConflict", err)
+	}
+
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Got %s but expected the gRPC code to be preserved", status.Code(err))
+	}
+
+	if Wrap("String.Update", "", "", err) != err {
+		t.Fatalf("An *Error should not be wrapped twice")
+	}
+
+	if Wrap("String.Update", "", "", nil) != nil {
+		t.Fatalf("Expected nil")
+	}
+
+	want := "fuego: String.Retrieve users/jsmith (FirstName): not found: document: doesn't exist: rpc error: code = NotFound desc = missing"
+	if err.Error() != want {
+		t.Fatalf("Got %q but expected %q", err.Error(), want)
+	}
+}
+
+func TestError_GRPCStatusFromKind(t *testing.T) {
+	err := New("Number.Retrieve", "users/jsmith", "Age", TypeMismatch, errors.New("got a string"))
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Got %s but expected %s", status.Code(err), codes.InvalidArgument)
+	}
+}
+
+func TestError_GRPCStatusOfConflicts(t *testing.T) {
+
+	tests := []struct {
+		description string
+		with        error
+		want        codes.Code
+	}{
+		{
+			description: "Domain conflict (e.g. a failed precondition)",
+			with:        errors.New("precondition failed"),
+			want:        codes.FailedPrecondition,
+		},
+		{
+			description: "Transaction aborted because of contention",
+			with:        status.Error(codes.Aborted, "too much contention"),
+			want:        codes.Aborted,
+		},
+		{
+			description: "Failed precondition reported by Firestore",
+			with:        status.Error(codes.FailedPrecondition, "stale update time"),
+			want:        codes.FailedPrecondition,
+		},
+	}
+
+	for _, test := range tests {
+		err := New("State.Transition", "orders/123", "Status", Conflict, test.with)
+
+		if status.Code(err) != test.want {
+			t.Fatalf("%s -> Got %s but expected %s", test.description, status.Code(err), test.want)
+		}
+	}
+}
+
+func TestError_GRPCStatusOfWrappedCodes(t *testing.T) {
+	precondition := errors.New("precondition failed")
+
+	for _, code := range []codes.Code{codes.FailedPrecondition, codes.NotFound} {
+		err := New("String.Update", "users/jsmith", "LastName", Conflict, fmt.Errorf("%w: %w", precondition, status.Error(code, "rejected")))
+
+		if status.Code(err) != code {
+			t.Fatalf("Got %s but expected %s", status.Code(err), code)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+// Package ids provides the strategies generating the IDs of the documents created by the fuego packages.
+package ids
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Strategy generates the ID of a document.
+//
+// data is the data the document is written with, nil if the ID is needed before any write.
+type Strategy func(data interface{}) (string, error)
+
+// ErrNoData is returned by the strategies deriving the ID from the data when no data is provided.
+var ErrNoData = errors.New("the ID is derived from the data, which has not been provided")
+
+const autoIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// Auto generates IDs in the format used by Firestore: 20 random alphanumeric characters.
+func Auto(interface{}) (string, error) {
+	const max = 256 / len(autoIDAlphabet) * len(autoIDAlphabet) // avoids the modulo bias
+
+	id := make([]byte, 0, 20)
+	b := make([]byte, 32)
+	for len(id) < cap(id) {
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+
+		for _, c := range b {
+			if int(c) < max && len(id) < cap(id) {
+				id = append(id, autoIDAlphabet[int(c)%len(autoIDAlphabet)])
+			}
+		}
+	}
+	return string(id), nil
+}
+
+// UUIDv4 generates random UUIDs (RFC 9562 version 4).
+func UUIDv4(interface{}) (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // variant 10
+	return formatUUID(u), nil
+}
+
+// UUIDv7 generates time-ordered UUIDs (RFC 9562 version 7): a millisecond timestamp followed by random bits.
+func UUIDv7(interface{}) (string, error) {
+	return uuidV7(time.Now())
+}
+
+func uuidV7(t time.Time) (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[6:]); err != nil {
+		return "", err
+	}
+
+	ms := uint64(t.UnixMilli())
+	u[0], u[1], u[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	u[3], u[4], u[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+
+	u[6] = u[6]&0x0f | 0x70 // version 7
+	u[8] = u[8]&0x3f | 0x80 // variant 10
+	return formatUUID(u), nil
+}
+
+func formatUUID(u [16]byte) string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID generates ULIDs: a millisecond timestamp followed by 80 random bits, encoded in Crockford's base32.
+func ULID(interface{}) (string, error) {
+	return ulid(time.Now())
+}
+
+func ulid(t time.Time) (string, error) {
+	var u [16]byte
+	binary.BigEndian.PutUint64(u[:8], uint64(t.UnixMilli())<<16)
+	if _, err := rand.Read(u[6:]); err != nil {
+		return "", err
+	}
+
+	// 128 bits encoded as 26 characters of 5 bits, the first one only holding 3 bits.
+	hi, lo := binary.BigEndian.Uint64(u[:8]), binary.BigEndian.Uint64(u[8:])
+	b := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		b[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b), nil
+}
+
+// Hash returns a Strategy deriving the ID from the values of the given fields of the data
+// (a map or a struct, honouring the `firestore` struct tags): the hex encoded SHA-256 of the values.
+//
+// The same values always give the same ID, which makes creations idempotent. The values are encoded canonically
+// (see encode): timestamps are compared as instants, pointers by the values they point to, maps regardless of
+// the order of their keys and structs as the maps Firestore stores them as (the fields of embedded structs included).
+func Hash(fields ...string) Strategy {
+	return func(data interface{}) (string, error) {
+		if data == nil {
+			return "", ErrNoData
+		}
+
+		h := sha256.New()
+		for _, field := range fields {
+			v, ok := lookup(reflect.ValueOf(data), strings.Split(field, "."))
+			if !ok {
+				return "", fmt.Errorf("the field %q is missing from the data", field)
+			}
+			h.Write([]byte(field + "="))
+			encode(h, reflect.ValueOf(v))
+			h.Write([]byte{0})
+		}
+
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+}
+
+// encode writes a canonical encoding of a value to w: values equal once stored in Firestore have the same encoding.
+//
+// Pointers and interfaces are dereferenced, integers share the same encoding whatever their size,
+// timestamps are encoded in UTC (RFC 3339, nanoseconds included), map keys are sorted
+// and structs are encoded as maps of their Firestore fields.
+func encode(w io.Writer, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			io.WriteString(w, "null")
+			return
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		io.WriteString(w, "null")
+		return
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		io.WriteString(w, "t:"+t.UTC().Format(time.RFC3339Nano))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		io.WriteString(w, "s:"+strconv.Quote(v.String()))
+	case reflect.Bool:
+		io.WriteString(w, "b:"+strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		io.WriteString(w, "i:"+strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		io.WriteString(w, "i:"+strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		io.WriteString(w, "f:"+strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			io.WriteString(w, "y:"+hex.EncodeToString(bytesOf(v)))
+			return
+		}
+
+		io.WriteString(w, "[")
+		for i := 0; i < v.Len(); i++ {
+			encode(w, v.Index(i))
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "]")
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			key := fmt.Sprint(it.Key().Interface())
+			keys = append(keys, key)
+			values[key] = it.Value()
+		}
+		encodeFields(w, keys, values)
+	case reflect.Struct:
+		values := structFields(v)
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		encodeFields(w, keys, values)
+	default:
+		fmt.Fprintf(w, "%T:%v", v.Interface(), v.Interface())
+	}
+}
+
+// encodeFields writes the canonical encoding of the fields of a map or a struct to w, sorted by key.
+func encodeFields(w io.Writer, keys []string, values map[string]reflect.Value) {
+	sort.Strings(keys)
+
+	io.WriteString(w, "{")
+	for _, key := range keys {
+		io.WriteString(w, strconv.Quote(key)+":")
+		encode(w, values[key])
+		io.WriteString(w, ",")
+	}
+	io.WriteString(w, "}")
+}
+
+// bytesOf returns the content of a slice or an array of bytes.
+func bytesOf(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+
+	b := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(b), v)
+	return b
+}
+
+// lookup returns the value at a given path of a map or a struct.
+func lookup(v reflect.Value, path []string) (interface{}, bool) {
+	for _, name := range path {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil, false
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return nil, false
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return nil, false
+			}
+		case reflect.Struct:
+			f, ok := structField(v, name)
+			if !ok {
+				return nil, false
+			}
+			v = f
+		default:
+			return nil, false
+		}
+	}
+
+	return v.Interface(), true
+}
+
+// structField returns the field of a struct named name in Firestore (i.e. its `firestore` tag or its Go name).
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	f, ok := structFields(v)[name]
+	return f, ok
+}
+
+// structFields returns the fields of a struct stored in Firestore, indexed by name.
+//
+// As Firestore does, the fields of the embedded structs without `firestore` tag are promoted: a field hides
+// the fields of the same name nested deeper, the fields of the same name at the same depth being dropped
+// (unless a single one of them is tagged).
+func structFields(v reflect.Value) map[string]reflect.Value {
+	type candidate struct {
+		value  reflect.Value
+		depth  int
+		tagged bool
+		count  int
+	}
+
+	found := make(map[string]*candidate)
+	var walk func(v reflect.Value, depth int, visited map[reflect.Type]bool)
+	walk = func(v reflect.Value, depth int, visited map[reflect.Type]bool) {
+		t := v.Type()
+		visited[t] = true
+		defer delete(visited, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if embedded, ok := promoted(v.Field(i), f); ok {
+				if embedded.IsValid() && !visited[embedded.Type()] {
+					walk(embedded, depth+1, visited)
+				}
+				continue
+			}
+
+			name, ok := firestoreName(f)
+			if !ok {
+				continue
+			}
+
+			tagName, _, _ := strings.Cut(f.Tag.Get("firestore"), ",")
+			tagged := len(tagName) > 0
+			switch c := found[name]; {
+			case c == nil || depth < c.depth || depth == c.depth && tagged && !c.tagged:
+				found[name] = &candidate{value: v.Field(i), depth: depth, tagged: tagged, count: 1}
+			case depth == c.depth && tagged == c.tagged:
+				c.count++
+			}
+		}
+	}
+	walk(v, 0, make(map[reflect.Type]bool))
+
+	fields := make(map[string]reflect.Value, len(found))
+	for name, c := range found {
+		if c.count == 1 {
+			fields[name] = c.value
+		}
+	}
+	return fields
+}
+
+// promoted returns the struct embedded in a field if its fields are promoted (i.e. an anonymous struct,
+// or pointer to a struct, without `firestore` tag name), false otherwise.
+// The returned value is invalid if the pointer is nil, there is no field to promote then.
+//
+// Timestamps are stored as such, their fields are never promoted.
+func promoted(v reflect.Value, f reflect.StructField) (reflect.Value, bool) {
+	if !f.Anonymous {
+		return reflect.Value{}, false
+	}
+
+	if name, _, _ := strings.Cut(f.Tag.Get("firestore"), ","); len(name) > 0 {
+		return reflect.Value{}, false
+	}
+
+	t := f.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		return reflect.Value{}, false
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, true
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+// firestoreName returns the name of a struct field in Firestore (i.e. its `firestore` tag or its Go name),
+// false if the field isn't stored.
+func firestoreName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+
+	tag, ok := f.Tag.Lookup("firestore")
+	if !ok {
+		return f.Name, true
+	}
+
+	tag, _, _ = strings.Cut(tag, ",")
+	if tag == "-" {
+		return "", false
+	}
+	if len(tag) == 0 {
+		return f.Name, true
+	}
+	return tag, true
+}
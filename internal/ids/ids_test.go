@@ -0,0 +1,282 @@
+package ids
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestStrategies_Format(t *testing.T) {
+
+	tests := []struct {
+		description string
+		strategy    Strategy
+		want        *regexp.Regexp
+	}{
+		{
+			description: "Auto",
+			strategy:    Auto,
+			want:        regexp.MustCompile(`^[A-Za-z0-9]{20}$`),
+		},
+		{
+			description: "UUIDv4",
+			strategy:    UUIDv4,
+			want:        regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+		{
+			description: "UUIDv7",
+			strategy:    UUIDv7,
+			want:        regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+		{
+			description: "ULID",
+			strategy:    ULID,
+			want:        regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`),
+		},
+	}
+
+	for _, test := range tests {
+		seen := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			id, err := test.strategy(nil)
+			if err != nil {
+				t.Fatalf("%s -> Unexpected error: %v", test.description, err)
+			}
+
+			if !test.want.MatchString(id) {
+				t.Fatalf("%s -> Got %q which doesn't match %v", test.description, id, test.want)
+			}
+
+			if seen[id] {
+				t.Fatalf("%s -> Got %q twice", test.description, id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func TestStrategies_TimeOrdered(t *testing.T) {
+
+	tests := []struct {
+		description string
+		generate    func(t time.Time) (string, error)
+	}{
+		{
+			description: "UUIDv7",
+			generate:    uuidV7,
+		},
+		{
+			description: "ULID",
+			generate:    ulid,
+		},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range tests {
+		generated := make([]string, 0, 10)
+		for i := 0; i < 10; i++ {
+			id, err := test.generate(start.Add(time.Duration(i) * time.Millisecond))
+			if err != nil {
+				t.Fatalf("%s -> Unexpected error: %v", test.description, err)
+			}
+			generated = append(generated, id)
+		}
+
+		if !sort.StringsAreSorted(generated) {
+			t.Fatalf("%s -> Got %v which isn't sorted", test.description, generated)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+
+	type Address struct {
+		City string `firestore:"City"`
+	}
+
+	type User struct {
+		Email   string  `firestore:"EmailAddress"`
+		Name    string  `firestore:"-"`
+		Address Address `firestore:"Address,omitempty"`
+	}
+
+	hash := Hash("EmailAddress", "Address.City")
+
+	fromStruct, err := hash(User{Email: "jsmith@email.com", Name: "John", Address: Address{City: "Dublin"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fromPointer, err := hash(&User{Email: "jsmith@email.com", Name: "Jane", Address: Address{City: "Dublin"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fromMap, err := hash(map[string]interface{}{
+		"EmailAddress": "jsmith@email.com",
+		"Address":      map[string]interface{}{"City": "Dublin"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fromStruct != fromPointer || fromStruct != fromMap {
+		t.Fatalf("The same values are expected to give the same ID, got %q, %q and %q", fromStruct, fromPointer, fromMap)
+	}
+
+	other, err := hash(User{Email: "jdoe@email.com", Address: Address{City: "Dublin"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if other == fromStruct {
+		t.Fatalf("Different values are expected to give different IDs, got %q twice", other)
+	}
+
+	if _, err := hash(nil); !errors.Is(err, ErrNoData) {
+		t.Fatalf("Got %v but expected %v", err, ErrNoData)
+	}
+
+	if _, err := Hash("Name")(User{Name: "John"}); err == nil {
+		t.Fatal("An ignored field is expected to be missing")
+	}
+}
+
+func TestHash_Time(t *testing.T) {
+	hash := Hash("CreatedAt")
+
+	// time.Now() holds a monotonic clock reading, which isn't part of the ID, nor is the location
+	now := time.Now()
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(map[string]bool)
+	for _, v := range []time.Time{now, now.Round(0), now.In(paris), now.UTC()} {
+		id, err := hash(map[string]interface{}{"CreatedAt": v})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids[id] = true
+	}
+
+	if len(ids) != 1 {
+		t.Fatalf("The same instant is expected to give the same ID, got %d IDs", len(ids))
+	}
+}
+
+func TestHash_Pointers(t *testing.T) {
+
+	type Address struct {
+		City *string `firestore:"City"`
+	}
+
+	type User struct {
+		Address *Address          `firestore:"Address"`
+		Tags    map[string]string `firestore:"Tags"`
+	}
+
+	hash := Hash("Address", "Tags")
+	newUser := func() User {
+		city := "Dublin"
+		return User{
+			Address: &Address{City: &city},
+			Tags:    map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"},
+		}
+	}
+
+	// Different pointers to the same values, maps iterated in different orders
+	first, err := hash(newUser())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		id, err := hash(newUser())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if id != first {
+			t.Fatalf("The same values are expected to give the same ID, got %q and %q", first, id)
+		}
+	}
+
+	fromMap, err := hash(map[string]interface{}{
+		"Address": map[string]interface{}{"City": "Dublin"},
+		"Tags":    map[string]interface{}{"d": "4", "c": "3", "b": "2", "a": "1"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fromMap != first {
+		t.Fatalf("A struct and a map with the same values are expected to give the same ID, got %q and %q", first, fromMap)
+	}
+
+	city := "Cork"
+	other, err := hash(User{Address: &Address{City: &city}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if other == first {
+		t.Fatalf("Different values are expected to give different IDs, got %q twice", other)
+	}
+}
+
+func TestHash_EmbeddedStructs(t *testing.T) {
+
+	type Contact struct {
+		Email string `firestore:"Email"`
+		Phone string `firestore:"Phone"`
+	}
+
+	type Audit struct {
+		CreatedBy string `firestore:"CreatedBy"`
+	}
+
+	type User struct {
+		Contact
+		*Audit
+		Phone string `firestore:"Phone"` // hides Contact.Phone
+	}
+
+	user := User{Contact: Contact{Email: "jsmith@example.com", Phone: "hidden"}, Audit: &Audit{CreatedBy: "admin"}, Phone: "123"}
+
+	// The fields of the embedded structs are looked up as if they were part of the struct
+	id, err := Hash("Email")(user)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want, err := Hash("Email")(map[string]interface{}{"Email": "jsmith@example.com"})
+	if err != nil || id != want {
+		t.Fatalf("Got %s (%v) but expected %s", id, err, want)
+	}
+
+	// The struct gives the same ID as the map it is stored as
+	type Wrapper struct {
+		User User `firestore:"User"`
+	}
+
+	id, err = Hash("User")(Wrapper{User: user})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want, err = Hash("User")(map[string]interface{}{"User": map[string]interface{}{
+		"Email":     "jsmith@example.com",
+		"Phone":     "123",
+		"CreatedBy": "admin",
+	}})
+	if err != nil || id != want {
+		t.Fatalf("Got %s (%v) but expected %s", id, err, want)
+	}
+
+	// A nil embedded pointer has no field
+	if _, err := Hash("CreatedBy")(User{}); err == nil {
+		t.Fatal("The fields of a nil embedded struct are expected to be missing")
+	}
+}
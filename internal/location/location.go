@@ -0,0 +1,46 @@
+// Package location provides a cache of the time locations used by the fuego packages.
+package location
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache caches the locations loaded from the IANA Time Zone database, which is otherwise read at every load.
+//
+// A nil *Cache is valid and loads the locations without caching them. Cache is safe for concurrent use.
+type Cache struct {
+	mu        sync.RWMutex
+	locations map[string]*time.Location
+}
+
+// NewCache creates and returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{locations: make(map[string]*time.Location)}
+}
+
+// Load returns the location with the given name (e.g. "America/Los_Angeles"), see time.LoadLocation.
+//
+// Errors are not cached.
+func (c *Cache) Load(name string) (*time.Location, error) {
+	if c == nil {
+		return time.LoadLocation(name)
+	}
+
+	c.mu.RLock()
+	loc, ok := c.locations[name]
+	c.mu.RUnlock()
+	if ok {
+		return loc, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.locations[name] = loc
+	c.mu.Unlock()
+	return loc, nil
+}
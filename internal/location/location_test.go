@@ -0,0 +1,69 @@
+package location
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCache_Load(t *testing.T) {
+	c := NewCache()
+
+	first, err := c.Load("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := c.Load("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second {
+		t.Fatalf("The location is expected to be cached")
+	}
+
+	if first.String() != "America/Los_Angeles" {
+		t.Fatalf("Got %s but expected %s", first, "America/Los_Angeles")
+	}
+}
+
+func TestCache_Load_Invalid(t *testing.T) {
+	c := NewCache()
+
+	if _, err := c.Load("Nowhere/Somewhere"); err == nil {
+		t.Fatalf("Expected an error")
+	}
+
+	if len(c.locations) != 0 {
+		t.Fatalf("Errors are not expected to be cached, got %v", c.locations)
+	}
+}
+
+func TestCache_Load_Nil(t *testing.T) {
+	var c *Cache
+
+	loc, err := c.Load("Europe/Dublin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loc.String() != "Europe/Dublin" {
+		t.Fatalf("Got %s but expected %s", loc, "Europe/Dublin")
+	}
+}
+
+func TestCache_Load_Concurrent(t *testing.T) {
+	c := NewCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Load("Asia/Tokyo"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,161 @@
+// Package logging provides the structured logger shared by the fuego packages.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/remychantenay/fuego/internal/errs"
+)
+
+// Redacted is the value logged in place of a redacted field value.
+const Redacted = "[REDACTED]"
+
+// Redactor returns the value to log for a given field.
+//
+// The field name is empty when the value is a whole document.
+type Redactor func(field string, value interface{}) interface{}
+
+// RedactAll replaces every field value with Redacted.
+func RedactAll(field string, value interface{}) interface{} {
+	return Redacted
+}
+
+// RedactNone logs field values as they are.
+func RedactNone(field string, value interface{}) interface{} {
+	return value
+}
+
+// RedactFields returns a Redactor only hiding the values of the given fields.
+// Whole documents are always redacted.
+//
+// Nested fields are dot-separated: the fields nested in a hidden field (e.g. "Tokens.Android" for "Tokens")
+// are hidden, as well as the fields a hidden field is nested in (e.g. "Tokens" for "Tokens.Android").
+func RedactFields(fields ...string) Redactor {
+	hidden := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		hidden[f] = struct{}{}
+	}
+
+	return func(field string, value interface{}) interface{} {
+		if len(field) == 0 {
+			return Redacted
+		}
+
+		for h := range hidden {
+			if field == h || strings.HasPrefix(field, h+".") || strings.HasPrefix(h, field+".") {
+				return Redacted
+			}
+		}
+		return value
+	}
+}
+
+// Logger wraps a slog.Logger with the fuego conventions:
+// operations are logged at debug level, batch commits and transaction retries at info level
+// and failures at error level.
+//
+// A nil Logger is valid and logs nothing.
+type Logger struct {
+	logger *slog.Logger
+	redact Redactor
+}
+
+// New creates and returns a new Logger.
+//
+// nil is returned if l is nil. If redact is nil, all the field values are redacted.
+func New(l *slog.Logger, redact Redactor) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	if redact == nil {
+		redact = RedactAll
+	}
+
+	return &Logger{
+		logger: l,
+		redact: redact,
+	}
+}
+
+// Slog returns the underlying slog.Logger, nil if l is nil.
+func (l *Logger) Slog() *slog.Logger {
+	if l == nil {
+		return nil
+	}
+	return l.logger
+}
+
+// Redactor returns the Redactor of l, nil if l is nil.
+func (l *Logger) Redactor() Redactor {
+	if l == nil {
+		return nil
+	}
+	return l.redact
+}
+
+// Value returns an attribute holding the (possibly redacted) value of a field.
+func (l *Logger) Value(field string, value interface{}) slog.Attr {
+	if l == nil {
+		return slog.Attr{}
+	}
+	return slog.Any("value", l.redact(field, value))
+}
+
+// Values returns an attribute holding the (possibly redacted) values of several fields.
+func (l *Logger) Values(fields []string, values []interface{}) slog.Attr {
+	if l == nil {
+		return slog.Attr{}
+	}
+
+	attrs := make([]any, len(fields))
+	for i, field := range fields {
+		attrs[i] = slog.Any(field, l.redact(field, values[i]))
+	}
+	return slog.Group("values", attrs...)
+}
+
+// Operation logs an operation about to be performed on a given path.
+func (l *Logger) Operation(ctx context.Context, op, path string, attrs ...slog.Attr) {
+	l.log(ctx, slog.LevelDebug, "fuego: operation", op, path, attrs)
+}
+
+// BatchCommit logs the commit of a write batch.
+func (l *Logger) BatchCommit(ctx context.Context, op, path string, attrs ...slog.Attr) {
+	l.log(ctx, slog.LevelInfo, "fuego: batch commit", op, path, attrs)
+}
+
+// TransactionRetry logs a transaction being attempted again.
+func (l *Logger) TransactionRetry(ctx context.Context, op, path string, attempt int) {
+	l.log(ctx, slog.LevelInfo, "fuego: transaction retry", op, path, []slog.Attr{slog.Int("attempt", attempt)})
+}
+
+// Retry logs an operation being attempted again after a failure.
+func (l *Logger) Retry(ctx context.Context, op, path string, attempt int, wait time.Duration, err error) {
+	l.log(ctx, slog.LevelInfo, "fuego: retry", op, path, []slog.Attr{
+		slog.Int("attempt", attempt),
+		slog.Duration("wait", wait),
+		slog.Any("error", err),
+	})
+}
+
+// Failure logs a failed operation.
+func (l *Logger) Failure(ctx context.Context, op, path string, err error, attrs ...slog.Attr) {
+	l.log(ctx, slog.LevelError, "fuego: operation failed", op, path, append(attrs, slog.Any("error", err)))
+}
+
+func (l *Logger) log(ctx context.Context, level slog.Level, msg, op, path string, attrs []slog.Attr) {
+	if l == nil || !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	base := []slog.Attr{slog.String("op", op)}
+	if len(path) > 0 {
+		base = append(base, slog.String("path", errs.ShortPath(path)))
+	}
+
+	l.logger.LogAttrs(ctx, level, msg, append(base, attrs...)...)
+}
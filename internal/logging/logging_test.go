@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactors(t *testing.T) {
+
+	tests := []struct {
+		description string
+		redactor    Redactor
+		field       string
+		want        interface{}
+	}{
+		{
+			description: "RedactAll",
+			redactor:    RedactAll,
+			field:       "FirstName",
+			want:        Redacted,
+		},
+		{
+			description: "RedactNone",
+			redactor:    RedactNone,
+			field:       "FirstName",
+			want:        "John",
+		},
+		{
+			description: "RedactFields with a hidden field",
+			redactor:    RedactFields("Tokens", "FirstName"),
+			field:       "FirstName",
+			want:        Redacted,
+		},
+		{
+			description: "RedactFields with a visible field",
+			redactor:    RedactFields("Tokens"),
+			field:       "FirstName",
+			want:        "John",
+		},
+		{
+			description: "RedactFields with a field nested in a hidden field",
+			redactor:    RedactFields("Tokens"),
+			field:       "Tokens.Android",
+			want:        Redacted,
+		},
+		{
+			description: "RedactFields with a field holding a hidden field",
+			redactor:    RedactFields("Tokens.Android"),
+			field:       "Tokens",
+			want:        Redacted,
+		},
+		{
+			description: "RedactFields with a field sharing a prefix",
+			redactor:    RedactFields("Tokens"),
+			field:       "TokensCount",
+			want:        "John",
+		},
+		{
+			description: "RedactFields with a whole document",
+			redactor:    RedactFields("Tokens"),
+			field:       "",
+			want:        Redacted,
+		},
+	}
+
+	for _, test := range tests {
+		result := test.redactor(test.field, "John")
+
+		if result != test.want {
+			t.Fatalf("%s -> Got %v but expected %v", test.description, result, test.want)
+		}
+	}
+}
+
+func TestLogger_Values(t *testing.T) {
+	ctx := context.Background()
+
+	buf := &bytes.Buffer{}
+	l := New(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})), RedactFields("Tokens"))
+
+	l.Operation(ctx, "Document.Update", "users/jsmith", l.Values([]string{"FirstName", "Tokens"}, []interface{}{"John", "AND_123"}))
+	out := buf.String()
+	for _, want := range []string{"values.FirstName=John", "values.Tokens=" + Redacted} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Expected %q in %q", want, out)
+		}
+	}
+}
+
+func TestLogger_Levels(t *testing.T) {
+	ctx := context.Background()
+
+	buf := &bytes.Buffer{}
+	l := New(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo})), nil)
+
+	l.Operation(ctx, "String.Update", "projects/p/databases/(default)/documents/users/jsmith", l.Value("FirstName", "John"))
+	if buf.Len() != 0 {
+		t.Fatalf("Operations should be logged at debug level, got %q", buf.String())
+	}
+
+	l.Failure(ctx, "String.Update", "projects/p/databases/(default)/documents/users/jsmith", errors.New("boom"), l.Value("FirstName", "John"))
+	out := buf.String()
+	for _, want := range []string{"level=ERROR", "op=String.Update", "path=users/jsmith", "value=" + Redacted, "error=boom"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Expected %q in %q", want, out)
+		}
+	}
+}
+
+func TestLogger_Nil(t *testing.T) {
+	var l *Logger
+	if New(nil, RedactNone) != nil {
+		t.Fatalf("Expected a nil Logger")
+	}
+
+	if l.Slog() != nil || l.Redactor() != nil {
+		t.Fatalf("Expected a nil slog.Logger and Redactor")
+	}
+
+	// Must not panic.
+	l.Operation(context.Background(), "String.Update", "users/jsmith", l.Value("FirstName", "John"))
+	l.TransactionRetry(context.Background(), "Number.Increment", "users/jsmith", 2)
+}
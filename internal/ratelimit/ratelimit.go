@@ -0,0 +1,287 @@
+// Package ratelimit provides the client-side rate limiting applied to the operations performed by the fuego packages.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter throttles operations.
+type Limiter interface {
+
+	// Wait blocks until n operations are allowed or ctx is done.
+	Wait(ctx context.Context, n int) error
+}
+
+// RampUp describes a traffic ramp-up: the rate starts at Initial operations per second
+// and is increased by Increase (e.g. 0.5 for 50%) Every period, until there is no operation for IdleReset.
+type RampUp struct {
+
+	// Initial is the number of operations per second allowed at first.
+	Initial float64
+
+	// Increase is the increase ratio applied every period.
+	Increase float64
+
+	// Every is the period between two increases.
+	Every time.Duration
+
+	// IdleReset is the period without operations after which the ramp-up starts over (never if zero).
+	IdleReset time.Duration
+}
+
+// FiveFiftyFive is the 500/50/5 rule recommended by Firestore for new collections:
+// start at 500 operations per second and increase the traffic by 50% every 5 minutes.
+// The ramp-up starts over after 5 minutes without operations.
+var FiveFiftyFive = RampUp{
+	Initial:   500,
+	Increase:  0.5,
+	Every:     5 * time.Minute,
+	IdleReset: 5 * time.Minute,
+}
+
+// rate returns the rate after a given elapsed time.
+func (r RampUp) rate(elapsed time.Duration) float64 {
+	if r.Every <= 0 {
+		return r.Initial
+	}
+
+	periods := math.Floor(float64(elapsed) / float64(r.Every))
+	return r.Initial * math.Pow(1+r.Increase, periods)
+}
+
+// TokenBucket is a token bucket Limiter.
+//
+// Operations exceeding the available tokens are allowed once the bucket has refilled enough,
+// the bucket can therefore go into debt when n is greater than the burst.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   func(elapsed time.Duration) float64
+	burst  float64
+	tokens float64
+	idle   time.Duration
+	start  time.Time
+	last   time.Time
+
+	now func() time.Time
+}
+
+// NewTokenBucket creates and returns a TokenBucket allowing rate operations per second, with bursts of up to burst operations.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return newTokenBucket(func(time.Duration) float64 { return rate }, burst)
+}
+
+// NewRampUp creates and returns a TokenBucket following a given ramp-up.
+//
+// The ramp-up starts with the first operation, and starts over after r.IdleReset without operations.
+// Bursts are limited to one second worth of operations.
+func NewRampUp(r RampUp) *TokenBucket {
+	b := newTokenBucket(r.rate, int(r.Initial))
+	b.idle = r.IdleReset
+	return b
+}
+
+func newTokenBucket(rate func(time.Duration) float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// Wait blocks until n operations are allowed or ctx is done.
+func (b *TokenBucket) Wait(ctx context.Context, n int) error {
+	if err := sleep(ctx, b.reserve(n)); err != nil {
+		b.cancel(n)
+		return err
+	}
+	return nil
+}
+
+// reserve takes n tokens and returns how long to wait before they are available.
+func (b *TokenBucket) reserve(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if b.start.IsZero() || (b.idle > 0 && now.Sub(b.last) > b.idle) {
+		b.start = now
+	}
+	if b.last.IsZero() {
+		b.last = now
+	}
+
+	rate := b.rate(now.Sub(b.start))
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	b.tokens -= float64(n)
+	if b.tokens >= 0 || rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / rate * float64(time.Second))
+}
+
+// cancel gives back n tokens.
+func (b *TokenBucket) cancel(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+float64(n))
+}
+
+// All returns a Limiter waiting for all the given limiters (nil ones are ignored).
+func All(limiters ...Limiter) Limiter {
+	l := make(all, 0, len(limiters))
+	for _, limiter := range limiters {
+		if limiter != nil {
+			l = append(l, limiter)
+		}
+	}
+
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+type all []Limiter
+
+// Wait reserves the tokens of all the limiters supporting it (e.g. TokenBucket) first and waits for the longest of them,
+// then waits on the other limiters one after the other.
+// The reserved tokens are given back if ctx is done in the meantime.
+func (a all) Wait(ctx context.Context, n int) error {
+	var reserved []reserver
+	var others []Limiter
+	var wait time.Duration
+	for _, l := range a {
+		if r, ok := l.(reserver); ok {
+			reserved = append(reserved, r)
+			wait = max(wait, r.reserve(n))
+		} else {
+			others = append(others, l)
+		}
+	}
+
+	refund := func() {
+		for _, r := range reserved {
+			r.cancel(n)
+		}
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		refund()
+		return err
+	}
+
+	for _, l := range others {
+		if err := l.Wait(ctx, n); err != nil {
+			refund()
+			return err
+		}
+	}
+	return nil
+}
+
+// reserver is implemented by the limiters which tokens can be taken ahead of time and given back.
+type reserver interface {
+	reserve(n int) time.Duration
+	cancel(n int)
+}
+
+// sleep blocks for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	select {
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Wait waits on a Limiter, which can be nil.
+func Wait(ctx context.Context, l Limiter, n int) error {
+	if l == nil {
+		return nil
+	}
+	return l.Wait(ctx, n)
+}
+
+// Registry holds the global and per-collection limiters of a client.
+//
+// A nil *Registry is valid and doesn't limit anything.
+type Registry struct {
+	mu          sync.Mutex
+	global      Limiter
+	collections map[string]Limiter
+	rampUp      *RampUp
+	ramps       map[string]Limiter
+}
+
+// SetGlobal sets the limiter applying to all the operations.
+func (r *Registry) SetGlobal(l Limiter) {
+	r.global = l
+}
+
+// SetCollection sets the limiter applying to the operations on a given collection.
+func (r *Registry) SetCollection(path string, l Limiter) {
+	if r.collections == nil {
+		r.collections = make(map[string]Limiter)
+	}
+	r.collections[path] = l
+}
+
+// SetRampUp sets the ramp-up applying to the bulk operations of each collection.
+func (r *Registry) SetRampUp(ramp RampUp) {
+	r.rampUp = &ramp
+}
+
+// For returns the limiter applying to the operations on a given collection (nil if none).
+func (r *Registry) For(path string) Limiter {
+	if r == nil {
+		return nil
+	}
+	return All(r.global, r.collections[path])
+}
+
+// Bulk returns the limiter applying to the bulk operations on a given collection (nil if none).
+//
+// The ramp-up (if any) is shared by all the bulk operations on the collection.
+func (r *Registry) Bulk(path string) Limiter {
+	if r == nil {
+		return nil
+	}
+
+	if r.rampUp == nil {
+		return r.For(path)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ramps == nil {
+		r.ramps = make(map[string]Limiter)
+	}
+
+	ramp, ok := r.ramps[path]
+	if !ok {
+		ramp = NewRampUp(*r.rampUp)
+		r.ramps[path] = ramp
+	}
+
+	return All(r.global, r.collections[path], ramp)
+}
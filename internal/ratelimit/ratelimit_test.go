@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRampUp_Rate(t *testing.T) {
+
+	tests := []struct {
+		description string
+		elapsed     time.Duration
+		want        float64
+	}{
+		{
+			description: "At start",
+			elapsed:     0,
+			want:        500,
+		},
+		{
+			description: "Before the first increase",
+			elapsed:     4*time.Minute + 59*time.Second,
+			want:        500,
+		},
+		{
+			description: "After 5 minutes",
+			elapsed:     5 * time.Minute,
+			want:        750,
+		},
+		{
+			description: "After 10 minutes",
+			elapsed:     10 * time.Minute,
+			want:        1125,
+		},
+	}
+
+	for _, test := range tests {
+		result := FiveFiftyFive.rate(test.elapsed)
+
+		if result != test.want {
+			t.Fatalf("%s -> Got %f but expected %f", test.description, result, test.want)
+		}
+	}
+}
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	b := NewTokenBucket(10, 5)
+	b.now = func() time.Time { return now }
+
+	// 1. The burst is available straight away
+	if wait := b.reserve(5); wait != 0 {
+		t.Fatalf("Got a %v wait but expected none", wait)
+	}
+
+	// 2. The bucket is empty: 1 token takes 100ms to refill
+	if wait := b.reserve(1); wait != 100*time.Millisecond {
+		t.Fatalf("Got a %v wait but expected 100ms", wait)
+	}
+
+	// 3. After a second, the bucket is full again (debt included)
+	now = now.Add(time.Second)
+	if wait := b.reserve(4); wait != 0 {
+		t.Fatalf("Got a %v wait but expected none", wait)
+	}
+
+	// 4. More than the burst: the bucket goes into debt
+	now = now.Add(time.Second)
+	if wait := b.reserve(25); wait != 2*time.Second {
+		t.Fatalf("Got a %v wait but expected 2s", wait)
+	}
+}
+
+func TestTokenBucket_Wait(t *testing.T) {
+	b := NewTokenBucket(1, 1)
+
+	if err := b.Wait(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx, 1); err != context.DeadlineExceeded {
+		t.Fatalf("Got %v but expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestAll_Wait_Refund(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	available := NewTokenBucket(1, 1)
+	available.now = func() time.Time { return now }
+	empty := NewTokenBucket(1, 1)
+	empty.now = available.now
+	empty.reserve(1)
+
+	// The token of the available bucket is given back when waiting for the empty one is cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := All(available, empty).Wait(ctx, 1); err != context.DeadlineExceeded {
+		t.Fatalf("Got %v but expected %v", err, context.DeadlineExceeded)
+	}
+
+	if wait := available.reserve(1); wait != 0 {
+		t.Fatalf("Got a %v wait but expected none", wait)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	var nilRegistry *Registry
+	if nilRegistry.For("users") != nil || nilRegistry.Bulk("users") != nil {
+		t.Fatalf("A nil *Registry should not limit anything")
+	}
+
+	r := &Registry{}
+	if r.For("users") != nil || r.Bulk("users") != nil {
+		t.Fatalf("An empty Registry should not limit anything")
+	}
+
+	r.SetCollection("users", NewTokenBucket(10, 1))
+	if r.For("users") == nil {
+		t.Fatalf("Expected a limiter for users")
+	}
+	if r.For("orders") != nil {
+		t.Fatalf("Expected no limiter for orders")
+	}
+
+	r.SetRampUp(FiveFiftyFive)
+	if r.Bulk("orders") == nil {
+		t.Fatalf("Expected a ramp-up for orders")
+	}
+	if r.Bulk("orders").(all)[0] != r.Bulk("orders").(all)[0] {
+		t.Fatalf("The ramp-up should be shared by the bulk operations")
+	}
+}
+
+func TestTokenBucket_RampUp_IdleReset(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	b := NewRampUp(FiveFiftyFive)
+	b.now = func() time.Time { return now }
+
+	// 1. After 10 minutes of traffic, the rate is 1125 ops/s: a debt of 1125 operations takes 1s to refill
+	for i := 0; i < 10; i++ {
+		b.reserve(1)
+		now = now.Add(time.Minute)
+	}
+
+	if wait := b.reserve(500 + 1125); wait != time.Second {
+		t.Fatalf("Got a %v wait but expected 1s", wait)
+	}
+
+	// 2. After a long idle period, the ramp-up starts over at 500 ops/s
+	now = now.Add(time.Hour)
+	if wait := b.reserve(500 + 1125); wait != 2250*time.Millisecond {
+		t.Fatalf("Got a %v wait but expected 2.25s", wait)
+	}
+}
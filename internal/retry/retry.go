@@ -0,0 +1,180 @@
+// Package retry provides the retry policy applied to the operations performed by the fuego packages.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Idempotency tells whether an operation can safely be applied more than once.
+type Idempotency int
+
+const (
+	// Idempotent operations (e.g. setting a field to a given value) are retried on any retryable code.
+	Idempotent Idempotency = iota
+
+	// NonIdempotent operations (e.g. incrementing a field) are only retried when the failure
+	// guarantees that nothing has been written (see SafeCodes).
+	NonIdempotent
+)
+
+// DefaultRetryableCodes are the codes retried when a Policy doesn't specify any.
+var DefaultRetryableCodes = []codes.Code{
+	codes.Unavailable,
+	codes.ResourceExhausted,
+	codes.Aborted,
+}
+
+// SafeCodes are the codes for which the write is known to have been rejected before being applied.
+// NonIdempotent operations are only retried on those.
+//
+// On the other hand, an Unavailable or DeadlineExceeded error may happen after the write has been applied.
+var SafeCodes = []codes.Code{
+	codes.ResourceExhausted,
+	codes.Aborted,
+}
+
+// Policy describes how failed operations are retried.
+//
+// The zero value doesn't retry.
+type Policy struct {
+
+	// MaxAttempts is the max. number of attempts, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between two attempts.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor applied to the backoff after each retry.
+	// Defaults to 2.
+	Multiplier float64
+
+	// Jitter is the fraction (between 0 and 1) of the backoff that is randomized.
+	Jitter float64
+
+	// RetryableCodes are the gRPC codes worth retrying.
+	// Defaults to DefaultRetryableCodes.
+	RetryableCodes []codes.Code
+}
+
+// DefaultPolicy is a sensible policy for most workloads.
+var DefaultPolicy = Policy{
+	MaxAttempts:    5,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.5,
+}
+
+// maxBackoff is the longest backoff when a Policy has no max.: the largest float64 below math.MaxInt64,
+// so that it converts to a valid time.Duration.
+var maxBackoff = math.Nextafter(float64(math.MaxInt64), 0)
+
+// Policies holds a default Policy and its per-operation overrides.
+//
+// A nil *Policies is valid and doesn't retry.
+type Policies struct {
+
+	// Default applies to every operation without an override.
+	Default Policy
+
+	// Operations are the per-operation overrides, indexed by operation name (e.g. "String.Update").
+	Operations map[string]Policy
+}
+
+// For returns the policy applying to a given operation.
+func (p *Policies) For(op string) Policy {
+	if p == nil {
+		return Policy{}
+	}
+
+	if o, ok := p.Operations[op]; ok {
+		return o
+	}
+	return p.Default
+}
+
+// Do calls fn until it succeeds, the policy gives up or ctx is done.
+//
+// onRetry (optional) is called before waiting for the next attempt.
+func (p Policy) Do(ctx context.Context, idem Idempotency, fn func(ctx context.Context) error, onRetry func(attempt int, wait time.Duration, err error)) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= p.MaxAttempts || !p.Retryable(err, idem) {
+			return err
+		}
+
+		wait := p.Backoff(attempt, rand.Float64())
+		if onRetry != nil {
+			onRetry(attempt+1, wait, err)
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// Retryable returns true if an operation that failed with err can be retried.
+func (p Policy) Retryable(err error, idem Idempotency) bool {
+	code := status.Code(err)
+
+	retryable := p.RetryableCodes
+	if retryable == nil {
+		retryable = DefaultRetryableCodes
+	}
+
+	if !contains(retryable, code) {
+		return false
+	}
+
+	return idem == Idempotent || contains(SafeCodes, code)
+}
+
+// Backoff returns the wait after a given (failed) attempt.
+//
+// r is a random number in [0, 1) used to apply the jitter.
+func (p Policy) Backoff(attempt int, r float64) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	limit := maxBackoff
+	if p.MaxBackoff > 0 {
+		limit = float64(p.MaxBackoff)
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	switch {
+	case math.IsNaN(backoff): // no initial backoff and an overflowing multiplier (0 * +Inf)
+		backoff = 0
+	case backoff > limit:
+		backoff = limit
+	}
+
+	jitter := math.Min(math.Max(p.Jitter, 0), 1)
+	return time.Duration(backoff * (1 - jitter*r))
+}
+
+func contains(list []codes.Code, code codes.Code) bool {
+	for _, c := range list {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
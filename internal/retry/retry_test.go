@@ -0,0 +1,188 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPolicy_Backoff(t *testing.T) {
+
+	p := Policy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+
+	tests := []struct {
+		description string
+		attempt     int
+		random      float64
+		want        time.Duration
+	}{
+		{
+			description: "First attempt without jitter",
+			attempt:     1,
+			random:      0,
+			want:        100 * time.Millisecond,
+		},
+		{
+			description: "Third attempt without jitter",
+			attempt:     3,
+			random:      0,
+			want:        400 * time.Millisecond,
+		},
+		{
+			description: "Capped",
+			attempt:     10,
+			random:      0,
+			want:        time.Second,
+		},
+		{
+			description: "With jitter",
+			attempt:     2,
+			random:      0.5,
+			want:        150 * time.Millisecond,
+		},
+	}
+
+	for _, test := range tests {
+		result := p.Backoff(test.attempt, test.random)
+
+		if result != test.want {
+			t.Fatalf("%s -> Got %v but expected %v", test.description, result, test.want)
+		}
+	}
+}
+
+func TestPolicy_Backoff_Overflow(t *testing.T) {
+	p := Policy{InitialBackoff: 100 * time.Millisecond, Multiplier: 10}
+
+	// 10^9999 overflows to +Inf
+	if result := p.Backoff(10_000, 0); result != time.Duration(maxBackoff) {
+		t.Fatalf("Got %v but expected the max. backoff", result)
+	}
+
+	p.InitialBackoff = 0
+	if result := p.Backoff(10_000, 0); result != 0 {
+		t.Fatalf("Got %v but expected 0", result)
+	}
+}
+
+func TestPolicy_Retryable(t *testing.T) {
+
+	tests := []struct {
+		description string
+		policy      Policy
+		err         error
+		idempotency Idempotency
+		want        bool
+	}{
+		{
+			description: "Unavailable and idempotent",
+			err:         status.Error(codes.Unavailable, ""),
+			idempotency: Idempotent,
+			want:        true,
+		},
+		{
+			description: "Unavailable and non-idempotent",
+			err:         status.Error(codes.Unavailable, ""),
+			idempotency: NonIdempotent,
+			want:        false,
+		},
+		{
+			description: "ResourceExhausted and non-idempotent",
+			err:         status.Error(codes.ResourceExhausted, ""),
+			idempotency: NonIdempotent,
+			want:        true,
+		},
+		{
+			description: "NotFound",
+			err:         status.Error(codes.NotFound, ""),
+			idempotency: Idempotent,
+			want:        false,
+		},
+		{
+			description: "Not a gRPC error",
+			err:         errors.New("boom"),
+			idempotency: Idempotent,
+			want:        false,
+		},
+		{
+			description: "Custom codes",
+			policy:      Policy{RetryableCodes: []codes.Code{codes.Internal}},
+			err:         status.Error(codes.Unavailable, ""),
+			idempotency: Idempotent,
+			want:        false,
+		},
+	}
+
+	for _, test := range tests {
+		result := test.policy.Retryable(test.err, test.idempotency)
+
+		if result != test.want {
+			t.Fatalf("%s -> Got %t but expected %t", test.description, result, test.want)
+		}
+	}
+}
+
+func TestPolicy_Do(t *testing.T) {
+	ctx := context.Background()
+	p := Policy{MaxAttempts: 3}
+
+	attempts := 0
+	retries := 0
+	err := p.Do(ctx, Idempotent, func(ctx context.Context) error {
+		attempts++
+		return status.Error(codes.Unavailable, "")
+	}, func(attempt int, wait time.Duration, err error) {
+		retries++
+	})
+
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Got %v but expected an Unavailable error", err)
+	}
+
+	if attempts != 3 || retries != 2 {
+		t.Fatalf("Got %d attempts and %d retries but expected 3 and 2", attempts, retries)
+	}
+
+	attempts = 0
+	err = p.Do(ctx, Idempotent, func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return status.Error(codes.Aborted, "")
+		}
+		return nil
+	}, nil)
+	if err != nil || attempts != 2 {
+		t.Fatalf("Got %v after %d attempts but expected a success after 2 attempts", err, attempts)
+	}
+}
+
+func TestPolicies_For(t *testing.T) {
+	var nilPolicies *Policies
+	if nilPolicies.For("String.Update").MaxAttempts != 0 {
+		t.Fatalf("A nil *Policies should not retry")
+	}
+
+	p := &Policies{
+		Default: Policy{MaxAttempts: 5},
+		Operations: map[string]Policy{
+			"Document.Delete": {MaxAttempts: 1},
+		},
+	}
+
+	if p.For("String.Update").MaxAttempts != 5 {
+		t.Fatalf("Expected the default policy")
+	}
+
+	if p.For("Document.Delete").MaxAttempts != 1 {
+		t.Fatalf("Expected the overridden policy")
+	}
+}
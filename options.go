@@ -0,0 +1,191 @@
+package fuego
+
+import (
+	"log/slog"
+
+	"github.com/remychantenay/fuego/document"
+	"github.com/remychantenay/fuego/internal/ids"
+	"github.com/remychantenay/fuego/internal/logging"
+	"github.com/remychantenay/fuego/internal/ratelimit"
+	"github.com/remychantenay/fuego/internal/retry"
+)
+
+// Option configures a Fuego client.
+type Option func(*config)
+
+// Redactor returns the value to log for a given field.
+//
+// The field name is empty when the value is a whole document.
+type Redactor = logging.Redactor
+
+var (
+	// RedactAll hides every field value from the logs. This is the default.
+	RedactAll Redactor = logging.RedactAll
+
+	// RedactNone logs field values as they are.
+	RedactNone Redactor = logging.RedactNone
+)
+
+// RedactFields returns a Redactor only hiding the values of the given fields.
+// Whole documents are always redacted.
+func RedactFields(fields ...string) Redactor {
+	return logging.RedactFields(fields...)
+}
+
+// WithLogger enables logging with the given logger.
+//
+// Operations are logged at debug level, batch commits and transaction retries at info level
+// and failures at error level.
+//  fuegoClient := fuego.New(firestoreClient, fuego.WithLogger(slog.Default()))
+func WithLogger(l *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
+
+// WithLogRedaction sets how field values appear in the logs.
+//  fuegoClient := fuego.New(firestoreClient, fuego.WithLogger(logger), fuego.WithLogRedaction(fuego.RedactFields("Tokens")))
+func WithLogRedaction(r Redactor) Option {
+	return func(c *config) {
+		c.redact = r
+	}
+}
+
+// RetryPolicy describes how failed operations are retried
+// (max. attempts, exponential backoff with jitter and retryable codes).
+//
+// Non-idempotent operations (e.g. transactions) are only retried when the write is known not to have been applied.
+type RetryPolicy = retry.Policy
+
+// DefaultRetryPolicy retries Unavailable, ResourceExhausted and Aborted errors up to 5 times,
+// starting with a 100ms backoff.
+var DefaultRetryPolicy = retry.DefaultPolicy
+
+// WithRetryPolicy sets the retry policy applied to all the direct (i.e. not batched) operations
+// as well as to the batch commits.
+//
+// By default, failed operations are not retried.
+//  fuegoClient := fuego.New(firestoreClient, fuego.WithRetryPolicy(fuego.DefaultRetryPolicy))
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *config) {
+		c.retry().Default = p
+	}
+}
+
+// WithOperationRetryPolicy overrides the retry policy for a given operation (e.g. "String.Update", "Collection.SetForAll").
+//  fuegoClient := fuego.New(firestoreClient, fuego.WithOperationRetryPolicy("Document.Delete", fuego.RetryPolicy{}))
+func WithOperationRetryPolicy(op string, p RetryPolicy) Option {
+	return func(c *config) {
+		c.retry().Operations[op] = p
+	}
+}
+
+// WithRateLimit throttles all the reads and writes to opsPerSecond operations per second,
+// with bursts of up to burst operations (token bucket).
+//
+// The writes added to a write batch are not throttled, nor is their commit (see CommitBatch).
+//  fuegoClient := fuego.New(firestoreClient, fuego.WithRateLimit(1000, 100))
+func WithRateLimit(opsPerSecond float64, burst int) Option {
+	return func(c *config) {
+		c.rateLimits().SetGlobal(ratelimit.NewTokenBucket(opsPerSecond, burst))
+	}
+}
+
+// WithCollectionRateLimit throttles the reads and writes on a given collection (e.g. "users")
+// to opsPerSecond operations per second, with bursts of up to burst operations.
+//
+// It applies on top of the limit set with WithRateLimit (if any).
+//  fuegoClient := fuego.New(firestoreClient, fuego.WithCollectionRateLimit("users", 100, 10))
+func WithCollectionRateLimit(path string, opsPerSecond float64, burst int) Option {
+	return func(c *config) {
+		c.rateLimits().SetCollection(cleanPath(path), ratelimit.NewTokenBucket(opsPerSecond, burst))
+	}
+}
+
+// WithRampUp applies Firestore's 500/50/5 rule to the bulk operations (e.g. SetForAll, DeleteAll):
+// the traffic on each collection starts at 500 operations per second and is increased by 50% every 5 minutes.
+// The ramp-up of a collection starts over after 5 minutes without bulk operations on it.
+//
+// See https://firebase.google.com/docs/firestore/best-practices#ramping_up_traffic
+//  fuegoClient := fuego.New(firestoreClient, fuego.WithRampUp())
+func WithRampUp() Option {
+	return func(c *config) {
+		c.rateLimits().SetRampUp(ratelimit.FiveFiftyFive)
+	}
+}
+
+// WithConversions enables lenient conversions when retrieving typed field values
+// (e.g. a double without fractional part retrieved as a Number).
+//
+// By default, a field value of an unexpected type results in a TypeMismatch error.
+//  fuegoClient := fuego.New(firestoreClient, fuego.WithConversions(document.LenientConversions))
+func WithConversions(conversions document.Conversion) Option {
+	return func(c *config) {
+		c.conversions = conversions
+	}
+}
+
+// IDStrategy generates the ID of the documents created without one (see DocumentWithGeneratedID).
+//
+// data is the data the document is written with, nil if the ID is needed before any write.
+type IDStrategy = ids.Strategy
+
+var (
+	// AutoID generates IDs the same way as Firestore does (20 random alphanumeric characters). This is the default.
+	AutoID IDStrategy = ids.Auto
+
+	// UUIDv4 generates random UUIDs.
+	UUIDv4 IDStrategy = ids.UUIDv4
+
+	// UUIDv7 generates time-ordered UUIDs.
+	UUIDv7 IDStrategy = ids.UUIDv7
+
+	// ULID generates time-ordered, lexicographically sortable IDs.
+	ULID IDStrategy = ids.ULID
+)
+
+// HashID returns an IDStrategy deriving the ID from the values of the given fields (e.g. "EmailAddress", "Address.City")
+// of the data written, so that the same data always gets the same ID.
+//
+// The ID is allocated at the first write of the document, Create then fails with an AlreadyExists error on duplicates.
+func HashID(fields ...string) IDStrategy {
+	return ids.Hash(fields...)
+}
+
+// WithIDStrategy sets how the IDs of the documents created without one are generated.
+//  fuegoClient := fuego.New(firestoreClient, fuego.WithIDStrategy(fuego.UUIDv7))
+func WithIDStrategy(s IDStrategy) Option {
+	return func(c *config) {
+		c.ids = s
+	}
+}
+
+// config holds the settings provided to New.
+type config struct {
+	logger  *slog.Logger
+	redact  Redactor
+	retries *retry.Policies
+	limits  *ratelimit.Registry
+
+	conversions document.Conversion
+
+	ids IDStrategy
+}
+
+// rateLimits returns the rate limits, creating them if needed.
+func (c *config) rateLimits() *ratelimit.Registry {
+	if c.limits == nil {
+		c.limits = &ratelimit.Registry{}
+	}
+	return c.limits
+}
+
+// retry returns the retry policies, creating them if needed.
+func (c *config) retry() *retry.Policies {
+	if c.retries == nil {
+		c.retries = &retry.Policies{
+			Operations: make(map[string]retry.Policy),
+		}
+	}
+	return c.retries
+}
@@ -0,0 +1,20 @@
+package fuego
+
+import "github.com/remychantenay/fuego/document"
+
+// Update is a change applied to a field of a document (see document.FirestoreDocument.Update).
+//
+// Path is dot-separated (e.g. "Address.City"), FieldPath can be used instead for keys containing dots.
+//  err := fuegoClient.Document("users", "jsmith").Update(ctx,
+//  	fuego.Update{Path: "Address.City", Value: "Dublin"},
+//  	fuego.Update{Path: "LastSeenAt", Value: fuego.ServerTimestamp},
+//  )
+type Update = document.Update
+
+var (
+	// Delete is an Update value removing the field from the document.
+	Delete = document.Delete
+
+	// ServerTimestamp is an Update value setting the field to the time at which the write is applied.
+	ServerTimestamp = document.ServerTimestamp
+)